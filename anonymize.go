@@ -0,0 +1,61 @@
+package snowflake
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash"
+)
+
+// MinAnonymizerKeyLen is the shortest key NewAnonymizer accepts
+const MinAnonymizerKeyLen = 16
+
+var ErrWeakKey = errors.New("anonymizer key too short")
+
+// Anonymizer maps IDs to keyed pseudonyms for analytics exports: the first
+// 64 bits of HMAC-SHA256(key, id). The same key always maps an ID to the
+// same pseudonym, so exported tables stay joinable, while without the key
+// pseudonyms cannot be reversed or linked back to real IDs. Pseudonyms do
+// not preserve order or carry timestamps.
+//
+// Distinct IDs collide with probability about n²/2^65 over n IDs: roughly
+// 1 in 37 million for a million IDs and 3% for a billion. Use one key per
+// export to keep exports unlinkable to each other.
+type Anonymizer struct {
+	key []byte
+}
+
+// NewAnonymizer returns an Anonymizer keyed with key, which should be at
+// least MinAnonymizerKeyLen random bytes kept out of the export
+func NewAnonymizer(key []byte) (*Anonymizer, error) {
+	if len(key) < MinAnonymizerKeyLen {
+		return nil, fmt.Errorf("%w: %d bytes (min: %d)", ErrWeakKey, len(key), MinAnonymizerKeyLen)
+	}
+	return &Anonymizer{key: append([]byte(nil), key...)}, nil
+}
+
+// Anonymize returns the pseudonym of id
+func (a *Anonymizer) Anonymize(id uint64) uint64 {
+	return pseudonym(hmac.New(sha256.New, a.key), id)
+}
+
+// AnonymizeAll returns the pseudonyms of ids, in order. It reuses one HMAC
+// state across the batch.
+func (a *Anonymizer) AnonymizeAll(ids []uint64) []uint64 {
+	mac := hmac.New(sha256.New, a.key)
+	out := make([]uint64, len(ids))
+	for i, id := range ids {
+		out[i] = pseudonym(mac, id)
+	}
+	return out
+}
+
+// pseudonym computes the truncated HMAC of id with mac, resetting it first
+func pseudonym(mac hash.Hash, id uint64) uint64 {
+	var buf [sha256.Size]byte
+	mac.Reset()
+	mac.Write(binary.BigEndian.AppendUint64(buf[:0], id))
+	return binary.BigEndian.Uint64(mac.Sum(buf[:0]))
+}
@@ -0,0 +1,33 @@
+package snowflake
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestAnonymizer(t *testing.T) {
+	a, err := NewAnonymizer([]byte("0123456789abcdef"))
+	if err != nil {
+		t.Fatalf("Failed to create anonymizer: %v", err)
+	}
+
+	ids := []uint64{1, 2, 1634615694131200}
+	batch := a.AnonymizeAll(ids)
+	for i, id := range ids {
+		if got := a.Anonymize(id); got != batch[i] || got == id {
+			t.Errorf("ID %d: Anonymize %d, AnonymizeAll %d", id, got, batch[i])
+		}
+	}
+	if batch[0] == batch[1] {
+		t.Error("Expected distinct pseudonyms for distinct IDs")
+	}
+
+	other, _ := NewAnonymizer([]byte("fedcba9876543210"))
+	if other.Anonymize(1) == batch[0] {
+		t.Error("Expected different keys to give different pseudonyms")
+	}
+
+	if _, err := NewAnonymizer([]byte("short")); !errors.Is(err, ErrWeakKey) {
+		t.Errorf("Expected ErrWeakKey, got %v", err)
+	}
+}
@@ -0,0 +1,125 @@
+package snowflake
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// AuditEntry is a single issuance record
+type AuditEntry struct {
+	ID   uint64
+	Time time.Time
+	Tag  string
+
+	// Uncommitted marks an ID from a reserved block that was released
+	// without being committed
+	Uncommitted bool
+}
+
+// AuditLog is an append-only issuance log. Entries are buffered and written
+// asynchronously, one tab-separated line per ID:
+//
+//	<id>\t<time, RFC 3339>\t<caller tag>
+//
+// IDs from released reservations carry a fourth "uncommitted" column.
+//
+// The time is the issuing generator's clock reading, so it agrees with the
+// IDs' timestamps under a custom Clock. To rotate the log, write it to a
+// RotatingFile. When the buffer is full, Record blocks rather than
+// dropping entries.
+type AuditLog struct {
+	mu      sync.RWMutex
+	closed  bool
+	entries chan AuditEntry
+	done    chan struct{}
+	w       *bufio.Writer
+	err     error
+}
+
+// NewAuditLog starts an audit log writing to w, buffering up to bufferSize
+// pending entries
+func NewAuditLog(w io.Writer, bufferSize int) *AuditLog {
+	if bufferSize < 1 {
+		bufferSize = 1
+	}
+
+	a := &AuditLog{
+		entries: make(chan AuditEntry, bufferSize),
+		done:    make(chan struct{}),
+		w:       bufio.NewWriter(w),
+	}
+	go a.run()
+
+	return a
+}
+
+// Record queues an issuance record for id, issued at the clock reading at
+// on behalf of tag. Records made after Close are discarded.
+func (a *AuditLog) Record(id uint64, at time.Time, tag string) {
+	a.record(AuditEntry{ID: id, Time: at, Tag: tag})
+}
+
+func (a *AuditLog) record(entry AuditEntry) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	if a.closed {
+		return
+	}
+	a.entries <- entry
+}
+
+// Close flushes pending entries and stops the log. It returns the first
+// write error encountered, if any.
+func (a *AuditLog) Close() error {
+	a.mu.Lock()
+	if !a.closed {
+		a.closed = true
+		close(a.entries)
+	}
+	a.mu.Unlock()
+
+	<-a.done
+	return a.err
+}
+
+// run writes queued entries, flushing whenever the queue drains
+func (a *AuditLog) run() {
+	defer close(a.done)
+
+	for entry := range a.entries {
+		a.write(entry)
+
+	drain:
+		for {
+			select {
+			case entry, ok := <-a.entries:
+				if !ok {
+					break drain
+				}
+				a.write(entry)
+			default:
+				break drain
+			}
+		}
+
+		if err := a.w.Flush(); err != nil && a.err == nil {
+			a.err = err
+		}
+	}
+}
+
+func (a *AuditLog) write(entry AuditEntry) {
+	status := ""
+	if entry.Uncommitted {
+		status = "\tuncommitted"
+	}
+	_, err := fmt.Fprintf(a.w, "%d\t%s\t%s%s\n",
+		entry.ID, entry.Time.UTC().Format(time.RFC3339Nano), entry.Tag, status)
+	if err != nil && a.err == nil {
+		a.err = err
+	}
+}
@@ -0,0 +1,105 @@
+package snowflake
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestAuditLog(t *testing.T) {
+	var buf bytes.Buffer
+	audit := NewAuditLog(&buf, 16)
+
+	gen, err := NewGenerator(Config{Version: Version0, NodeID: 1, Audit: audit})
+	if err != nil {
+		t.Fatalf("Failed to create generator: %v", err)
+	}
+
+	const numIDs = 100
+	ids := make([]uint64, numIDs)
+	for i := 0; i < numIDs; i++ {
+		id, err := gen.NextIDTagged("backfill")
+		if err != nil {
+			t.Fatalf("Failed to generate ID: %v", err)
+		}
+		ids[i] = id
+	}
+
+	if err := audit.Close(); err != nil {
+		t.Fatalf("Failed to close audit log: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != numIDs {
+		t.Fatalf("Expected %d audit lines, got %d", numIDs, len(lines))
+	}
+
+	for i, line := range lines {
+		fields := strings.Split(line, "\t")
+		if len(fields) != 3 {
+			t.Fatalf("Line %d: expected 3 fields, got %q", i, line)
+		}
+
+		id, err := strconv.ParseUint(fields[0], 10, 64)
+		if err != nil || id != ids[i] {
+			t.Errorf("Line %d: expected ID %d, got %q", i, ids[i], fields[0])
+		}
+		if fields[2] != "backfill" {
+			t.Errorf("Line %d: expected tag backfill, got %q", i, fields[2])
+		}
+	}
+}
+
+func TestAuditLog_RecordAfterClose(t *testing.T) {
+	var buf bytes.Buffer
+	audit := NewAuditLog(&buf, 1)
+
+	if err := audit.Close(); err != nil {
+		t.Fatalf("Failed to close audit log: %v", err)
+	}
+
+	audit.Record(1, time.Now(), "late")
+	if buf.Len() != 0 {
+		t.Errorf("Expected no output after close, got %q", buf.String())
+	}
+}
+
+func TestAuditLog_ClockTime(t *testing.T) {
+	var buf bytes.Buffer
+	audit := NewAuditLog(&buf, 16)
+
+	at := time.Date(2026, 5, 1, 12, 0, 0, 123_456_789, time.UTC)
+	gen, err := NewGenerator(Config{Version: Version0, NodeID: 1, Audit: audit, Clock: fixedClock(at)})
+	if err != nil {
+		t.Fatalf("Failed to create generator: %v", err)
+	}
+
+	if _, err := gen.NextIDTagged("api"); err != nil {
+		t.Fatalf("Failed to generate ID: %v", err)
+	}
+	if _, err := gen.NextIDs(2); err != nil {
+		t.Fatalf("Failed to generate batch: %v", err)
+	}
+	block, err := gen.Reserve(1)
+	if err != nil {
+		t.Fatalf("Failed to reserve: %v", err)
+	}
+	block.Commit()
+
+	if err := audit.Close(); err != nil {
+		t.Fatalf("Failed to close audit log: %v", err)
+	}
+
+	// Entries carry the generator's clock reading, not the wall time
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("Expected 4 audit lines, got %d", len(lines))
+	}
+	for i, line := range lines {
+		if fields := strings.Split(line, "\t"); fields[1] != at.Format(time.RFC3339Nano) {
+			t.Errorf("Line %d: expected time %s, got %q", i, at.Format(time.RFC3339Nano), fields[1])
+		}
+	}
+}
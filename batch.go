@@ -0,0 +1,142 @@
+package snowflake
+
+import (
+	"context"
+	"fmt"
+	"math/bits"
+)
+
+// maxBatchPrealloc caps the IDs a batch allocates room for up front, so a
+// huge n bounded by a deadline or quota does not allocate for all of them
+const maxBatchPrealloc = 4096
+
+// NextIDs generates n unique, monotonically increasing IDs in one batch
+func (g *Generator) NextIDs(n int) ([]uint64, error) {
+	return g.NextIDsContext(context.Background(), n)
+}
+
+// NextIDsContext generates up to n IDs on behalf of the caller tagged in
+// ctx, stopping early once ctx is done or the caller's quota is spent. It
+// then returns the IDs minted so far together with the error, so
+// streaming writers can make progress under throughput ceilings instead
+// of failing whole batches. A wait for the next time unit that is already
+// in progress is not interrupted.
+//
+// The batch is filled a time unit at a time, releasing the generator to
+// other callers whenever the unit's sequence is spent, so their IDs may
+// fall between the batch's. With SmearBatches the generator is held for
+// the whole batch, which smearing needs.
+func (g *Generator) NextIDsContext(ctx context.Context, n int) ([]uint64, error) {
+	if n < 0 {
+		return nil, fmt.Errorf("invalid batch size: %d", n)
+	}
+
+	tag := CallerTag(ctx)
+	ids := make([]uint64, 0, min(n, maxBatchPrealloc))
+
+	if g.smear {
+		g.mu.Lock()
+		defer g.mu.Unlock()
+
+		err := g.fillBatch(ctx, tag, &ids, n, false)
+		g.smearBatch(ids)
+		g.recordBatch(ids, tag)
+		return ids, err
+	}
+
+	for {
+		g.mu.Lock()
+		start := len(ids)
+		err := g.fillBatch(ctx, tag, &ids, n, true)
+		g.recordBatch(ids[start:], tag)
+		g.mu.Unlock()
+
+		if err != nil || len(ids) == n {
+			return ids, err
+		}
+	}
+}
+
+// fillBatch appends up to n IDs to ids in total, stopping early once the
+// current time unit's sequence is spent if unitOnly is set. The caller
+// must hold g.mu.
+func (g *Generator) fillBatch(ctx context.Context, tag string, ids *[]uint64, n int, unitOnly bool) error {
+	for len(*ids) < n {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if g.quotas != nil && !g.quotas.Allow(tag) {
+			return ErrQuotaExceeded
+		}
+
+		id, err := g.nextID()
+		if err != nil {
+			if g.quotas != nil {
+				g.quotas.releaseN(tag, 1)
+			}
+			return err
+		}
+		*ids = append(*ids, id)
+
+		if unitOnly && g.sequence == g.layout.MaxSequence {
+			return nil
+		}
+	}
+	return nil
+}
+
+// recordBatch accounts for ids as issued. The caller must hold g.mu.
+func (g *Generator) recordBatch(ids []uint64, tag string) {
+	g.issued += uint64(len(ids))
+	if g.audit != nil {
+		for _, id := range ids {
+			g.audit.Record(id, g.lastRead, tag)
+		}
+	}
+}
+
+// smearBatch re-encodes a batch spanning several time units so its IDs are
+// spread evenly across every unit from the first to the last, rather than
+// front-loaded into the first. The generator held the lock throughout, so
+// all sequence numbers after the batch's first in those units are free.
+// The caller must hold g.mu.
+func (g *Generator) smearBatch(ids []uint64) {
+	if len(ids) < 2 {
+		return
+	}
+
+	first := (ids[0] >> g.timeShift) & g.layout.MaxTimestamp
+	last := (ids[len(ids)-1] >> g.timeShift) & g.layout.MaxTimestamp
+	if first == last {
+		return
+	}
+
+	n := uint64(len(ids))
+	units := last - first + 1
+	capacity := g.layout.MaxSequence + 1
+	sequence := (ids[0] >> g.seqShift) & g.layout.MaxSequence // first free slot in the first unit
+
+	// Unit u's fair share is share(u+1) - share(u). What the first unit
+	// has no room for carries over to the following units.
+	share := func(u uint64) uint64 {
+		hi, lo := bits.Mul64(u, n)
+		q, _ := bits.Div64(hi, lo, units)
+		return q
+	}
+
+	i, carry := 0, uint64(0)
+	for u := uint64(0); u < units; u++ {
+		want := carry + share(u+1) - share(u)
+		take := min(want, capacity-sequence)
+		carry = want - take
+
+		for j := uint64(0); j < take; j++ {
+			ids[i] = g.encode(first+u, sequence+j)
+			i++
+		}
+		if take > 0 {
+			g.sequence = sequence + take - 1
+		}
+		sequence = 0
+	}
+}
@@ -0,0 +1,155 @@
+package snowflake
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestNextIDs(t *testing.T) {
+	gen, err := NewGenerator(Config{Version: Version0, NodeID: 1})
+	if err != nil {
+		t.Fatalf("Failed to create generator: %v", err)
+	}
+
+	const numIDs = 1000
+	ids, err := gen.NextIDs(numIDs)
+	if err != nil {
+		t.Fatalf("Failed to generate batch: %v", err)
+	}
+	if len(ids) != numIDs {
+		t.Fatalf("Expected %d IDs, got %d", numIDs, len(ids))
+	}
+
+	for i := 1; i < len(ids); i++ {
+		if ids[i] <= ids[i-1] {
+			t.Fatalf("IDs not monotonically increasing: %d <= %d", ids[i], ids[i-1])
+		}
+	}
+}
+
+func TestNextIDsContext_PartialBatch(t *testing.T) {
+	gen, err := NewGenerator(Config{Version: Version0, NodeID: 1})
+	if err != nil {
+		t.Fatalf("Failed to create generator: %v", err)
+	}
+
+	// Sequence exhaustion limits a node to 256 IDs/ms, so a million IDs
+	// cannot be minted before the deadline.
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	const numIDs = 1_000_000
+	ids, err := gen.NextIDsContext(ctx, numIDs)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Expected context.DeadlineExceeded, got %v", err)
+	}
+	if len(ids) == 0 || len(ids) >= numIDs {
+		t.Errorf("Expected a partial batch, got %d IDs", len(ids))
+	}
+}
+
+func TestNextIDsContext_LargeBatch(t *testing.T) {
+	gen, err := NewGenerator(Config{Version: Version0, NodeID: 1})
+	if err != nil {
+		t.Fatalf("Failed to create generator: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	// A huge n is bounded by the deadline, not allocated up front, and
+	// does not hold other callers off until the deadline
+	done := make(chan []uint64)
+	go func() {
+		ids, _ := gen.NextIDsContext(ctx, 1<<40)
+		done <- ids
+	}()
+
+	time.Sleep(20 * time.Millisecond)
+	start := time.Now()
+	id, err := gen.NextID()
+	if err != nil {
+		t.Fatalf("NextID failed: %v", err)
+	}
+	if d := time.Since(start); d > 50*time.Millisecond {
+		t.Errorf("NextID waited %s behind a batch", d)
+	}
+
+	ids := <-done
+	if len(ids) == 0 {
+		t.Fatal("Expected a partial batch")
+	}
+	for _, batched := range ids {
+		if batched == id {
+			t.Fatalf("ID %d issued twice", id)
+		}
+	}
+}
+
+func TestNextIDs_InvalidSize(t *testing.T) {
+	gen, err := NewGenerator(Config{Version: Version0, NodeID: 1})
+	if err != nil {
+		t.Fatalf("Failed to create generator: %v", err)
+	}
+
+	if _, err := gen.NextIDs(-1); err == nil {
+		t.Error("Expected error for negative batch size")
+	}
+}
+
+func TestNextIDs_SmearBatches(t *testing.T) {
+	// The clock advances one millisecond every 300 reads, so a plain batch
+	// of 300 fills the first millisecond's 256 sequence numbers and puts
+	// the remaining 44 in the next
+	base := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	newClock := func() Clock {
+		reads := 0
+		return ClockFunc(func() (time.Time, error) {
+			reads++
+			return base.Add(time.Duration(reads/300) * time.Millisecond), nil
+		})
+	}
+	perUnit := func(ids []uint64) map[time.Time]int {
+		counts := make(map[time.Time]int)
+		for _, id := range ids {
+			decoded, _ := Decode(id)
+			counts[decoded.Time]++
+		}
+		return counts
+	}
+
+	plain, _ := NewGenerator(Config{Version: Version0, Clock: newClock()})
+	ids, err := plain.NextIDs(300)
+	if err != nil {
+		t.Fatalf("Failed to generate batch: %v", err)
+	}
+	if counts := perUnit(ids); counts[base] != 256 {
+		t.Fatalf("Expected front-loaded batch, got %v", counts)
+	}
+
+	gen, _ := NewGenerator(Config{Version: Version0, Clock: newClock(), SmearBatches: true})
+	ids, err = gen.NextIDs(300)
+	if err != nil {
+		t.Fatalf("Failed to generate batch: %v", err)
+	}
+
+	counts := perUnit(ids)
+	if counts[base] != 150 || counts[base.Add(time.Millisecond)] != 150 {
+		t.Errorf("Expected 150 IDs per millisecond, got %v", counts)
+	}
+	for i := 1; i < len(ids); i++ {
+		if ids[i] <= ids[i-1] {
+			t.Fatalf("IDs not increasing at %d: %d <= %d", i, ids[i], ids[i-1])
+		}
+	}
+
+	next, err := gen.NextID()
+	if err != nil {
+		t.Fatalf("Failed to generate ID: %v", err)
+	}
+	if next <= ids[len(ids)-1] {
+		t.Errorf("Expected ID after batch to exceed %d, got %d", ids[len(ids)-1], next)
+	}
+}
@@ -0,0 +1,12 @@
+module github.com/samarthasthan/snowflake/bench/compare
+
+go 1.25.3
+
+require (
+	github.com/bwmarrin/snowflake v0.3.0
+	github.com/rs/xid v1.6.0
+	github.com/samarthasthan/snowflake v0.0.0
+	github.com/sony/sonyflake v1.3.0
+)
+
+replace github.com/samarthasthan/snowflake => ../../
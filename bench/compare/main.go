@@ -0,0 +1,225 @@
+// Command compare measures this package's generators head to head with
+// other Go ID libraries on the same machine:
+//
+//	cd bench/compare && go run . -duration 2s -goroutines 4 -format markdown
+//
+// Each library generates IDs from the given number of goroutines for the
+// given duration. The harness lives in its own module so the libraries it
+// compares never reach users of the root package.
+//
+// Layouts differ in what they promise, which bounds their numbers: Version0
+// issues at most 256,000 IDs/s per node (256 per ms) and sonyflake 25,600
+// (256 per 10ms). xid is neither time-ordered within a second nor bounded
+// by a per-unit sequence, so its figure is an upper bound for any scheme.
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	bwmarrin "github.com/bwmarrin/snowflake"
+	"github.com/rs/xid"
+	"github.com/samarthasthan/snowflake"
+	"github.com/sony/sonyflake"
+)
+
+// contender is one library under test. setup returns a function issuing
+// one ID, safe for concurrent use.
+type contender struct {
+	name  string
+	setup func() (func() error, error)
+}
+
+var contenders = []contender{
+	{name: "samarthasthan/snowflake v0", setup: func() (func() error, error) {
+		return generatorFunc(snowflake.Config{Version: snowflake.Version0, NodeID: 1})
+	}},
+	{name: "samarthasthan/snowflake twitter", setup: func() (func() error, error) {
+		return generatorFunc(snowflake.Config{Layout: snowflake.TwitterLayout(), NodeID: 1})
+	}},
+	{name: "bwmarrin/snowflake", setup: func() (func() error, error) {
+		node, err := bwmarrin.NewNode(1)
+		if err != nil {
+			return nil, err
+		}
+		return func() error {
+			node.Generate()
+			return nil
+		}, nil
+	}},
+	{name: "sony/sonyflake", setup: func() (func() error, error) {
+		sf, err := sonyflake.New(sonyflake.Settings{
+			MachineID: func() (uint16, error) { return 1, nil },
+		})
+		if err != nil {
+			return nil, err
+		}
+		return func() error {
+			_, err := sf.NextID()
+			return err
+		}, nil
+	}},
+	{name: "rs/xid", setup: func() (func() error, error) {
+		return func() error {
+			xid.New()
+			return nil
+		}, nil
+	}},
+}
+
+// generatorFunc adapts a snowflake.Generator to a contender
+func generatorFunc(cfg snowflake.Config) (func() error, error) {
+	gen, err := snowflake.NewGenerator(cfg)
+	if err != nil {
+		return nil, err
+	}
+	return func() error {
+		_, err := gen.NextID()
+		return err
+	}, nil
+}
+
+// report is the machine-readable output
+type report struct {
+	GOOS       string   `json:"goos"`
+	GOARCH     string   `json:"goarch"`
+	CPUs       int      `json:"cpus"`
+	GoVersion  string   `json:"go_version"`
+	Goroutines int      `json:"goroutines"`
+	DurationNS int64    `json:"duration_ns"`
+	Results    []result `json:"results"`
+}
+
+// result is one library's measurement
+type result struct {
+	Library      string  `json:"library"`
+	IDs          int64   `json:"ids"`
+	IDsPerSecond float64 `json:"ids_per_second"`
+	NSPerID      float64 `json:"ns_per_id"`
+	AllocsPerID  float64 `json:"allocs_per_id"`
+}
+
+func main() {
+	if err := run(os.Args[1:], os.Stdout); err != nil {
+		fmt.Fprintf(os.Stderr, "compare: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string, w io.Writer) error {
+	fs := flag.NewFlagSet("compare", flag.ContinueOnError)
+	duration := fs.Duration("duration", time.Second, "how long to run each library")
+	goroutines := fs.Int("goroutines", 1, "concurrent callers sharing one generator")
+	format := fs.String("format", "markdown", "output format: markdown or json")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *duration <= 0 || *goroutines < 1 {
+		return errors.New("-duration and -goroutines must be positive")
+	}
+	if *format != "markdown" && *format != "json" {
+		return fmt.Errorf("unknown format %q", *format)
+	}
+
+	rep := report{
+		GOOS:       runtime.GOOS,
+		GOARCH:     runtime.GOARCH,
+		CPUs:       runtime.NumCPU(),
+		GoVersion:  runtime.Version(),
+		Goroutines: *goroutines,
+		DurationNS: duration.Nanoseconds(),
+	}
+	for _, c := range contenders {
+		next, err := c.setup()
+		if err != nil {
+			return fmt.Errorf("%s: %w", c.name, err)
+		}
+		r, err := measure(next, *goroutines, *duration)
+		if err != nil {
+			return fmt.Errorf("%s: %w", c.name, err)
+		}
+		r.Library = c.name
+		rep.Results = append(rep.Results, r)
+	}
+
+	if *format == "json" {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(rep)
+	}
+	return writeMarkdown(w, rep)
+}
+
+// measure calls next from goroutines callers until duration has passed
+func measure(next func() error, goroutines int, duration time.Duration) (result, error) {
+	var (
+		total atomic.Int64
+		stop  atomic.Bool
+		wg    sync.WaitGroup
+	)
+	errs := make([]error, goroutines)
+
+	var before, after runtime.MemStats
+	runtime.GC()
+	runtime.ReadMemStats(&before)
+
+	start := time.Now()
+	for w := range goroutines {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var n int64
+			// Check the stop flag every 256 IDs to keep it off the hot path
+			for !stop.Load() {
+				for range 256 {
+					if err := next(); err != nil {
+						errs[w] = err
+						stop.Store(true)
+						break
+					}
+					n++
+				}
+			}
+			total.Add(n)
+		}()
+	}
+	time.Sleep(duration)
+	stop.Store(true)
+	wg.Wait()
+	elapsed := time.Since(start)
+	runtime.ReadMemStats(&after)
+
+	if err := errors.Join(errs...); err != nil {
+		return result{}, err
+	}
+	n := total.Load()
+	return result{
+		IDs:          n,
+		IDsPerSecond: float64(n) / elapsed.Seconds(),
+		NSPerID:      float64(elapsed.Nanoseconds()) * float64(goroutines) / float64(n),
+		AllocsPerID:  float64(after.Mallocs-before.Mallocs) / float64(n),
+	}, nil
+}
+
+// writeMarkdown renders the report as a markdown table
+func writeMarkdown(w io.Writer, rep report) error {
+	fmt.Fprintf(w, "%s/%s, %d CPUs, %s, %d goroutine(s), %s per library\n\n",
+		rep.GOOS, rep.GOARCH, rep.CPUs, rep.GoVersion, rep.Goroutines, time.Duration(rep.DurationNS))
+	fmt.Fprintln(w, "| Library | IDs/s | ns/ID | allocs/ID |")
+	fmt.Fprintln(w, "| ------- | ----: | ----: | --------: |")
+	for _, r := range rep.Results {
+		if _, err := fmt.Fprintf(w, "| %s | %.0f | %.1f | %.2f |\n",
+			r.Library, r.IDsPerSecond, r.NSPerID, r.AllocsPerID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+package snowflake
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+var ErrInvalidRange = errors.New("invalid time range")
+
+// IDBounds returns the smallest and largest IDs of version v whose
+// timestamps fall within [from, to), across all node IDs. The bounds are
+// suitable for time-windowed `BETWEEN` queries on ID-keyed tables.
+func IDBounds(v Version, from, to time.Time) (lo, hi uint64, err error) {
+	layout, ok := lookupLayout(v)
+	if !ok {
+		return 0, 0, fmt.Errorf("%w: %d", ErrInvalidVersion, v)
+	}
+	return layout.bounds(from, to, 0, layout.MaxNodeID)
+}
+
+// NodeIDBounds is like IDBounds but restricted to a single node. IDs from
+// other nodes interleave within the bounds, so queries must also filter on
+// the node bits to select only that node's IDs.
+func NodeIDBounds(v Version, from, to time.Time, nodeID uint64) (lo, hi uint64, err error) {
+	layout, ok := lookupLayout(v)
+	if !ok {
+		return 0, 0, fmt.Errorf("%w: %d", ErrInvalidVersion, v)
+	}
+	if nodeID > layout.MaxNodeID {
+		return 0, 0, fmt.Errorf("%w: %d (max: %d)", ErrInvalidNodeID, nodeID, layout.MaxNodeID)
+	}
+	return layout.bounds(from, to, nodeID, nodeID)
+}
+
+// bounds computes the ID bounds for [from, to) and the given node ID range
+func (l *VersionLayout) bounds(from, to time.Time, minNode, maxNode uint64) (uint64, uint64, error) {
+	if !from.Before(to) {
+		return 0, 0, fmt.Errorf("%w: %s is not before %s", ErrInvalidRange,
+			from.Format(time.RFC3339), to.Format(time.RFC3339))
+	}
+	if !to.After(l.Epoch) {
+		return 0, 0, fmt.Errorf("%w: range ends before epoch %s", ErrInvalidRange,
+			l.Epoch.Format(time.RFC3339))
+	}
+
+	// Partially covered time units at either end are included
+	var loTS uint64
+	if from.After(l.Epoch) {
+		loTS = uint64(from.Sub(l.Epoch) / l.TimeUnit)
+	}
+	hiTS := uint64((to.Sub(l.Epoch)+l.TimeUnit-1)/l.TimeUnit) - 1
+
+	if loTS > l.MaxTimestamp {
+		return 0, 0, fmt.Errorf("%w: range starts after version %d is exhausted", ErrInvalidRange, l.Version)
+	}
+	if hiTS > l.MaxTimestamp {
+		hiTS = l.MaxTimestamp
+	}
+
+	return l.encode(loTS, minNode, 0), l.encode(hiTS, maxNode, l.MaxSequence) | l.fieldsMask(), nil
+}
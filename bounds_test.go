@@ -0,0 +1,80 @@
+package snowflake
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestIDBounds(t *testing.T) {
+	from := time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2026, 2, 2, 0, 0, 0, 0, time.UTC)
+
+	lo, hi, err := IDBounds(Version0, from, to)
+	if err != nil {
+		t.Fatalf("IDBounds failed: %v", err)
+	}
+
+	first, err := Decode(lo)
+	if err != nil {
+		t.Fatalf("Failed to decode lower bound: %v", err)
+	}
+	if !first.Time.Equal(from) || first.NodeID != 0 || first.Sequence != 0 {
+		t.Errorf("Unexpected lower bound: %s", first)
+	}
+
+	last, err := Decode(hi)
+	if err != nil {
+		t.Fatalf("Failed to decode upper bound: %v", err)
+	}
+	if !last.Time.Equal(to.Add(-time.Millisecond)) || last.NodeID != 255 || last.Sequence != 255 {
+		t.Errorf("Unexpected upper bound: %s", last)
+	}
+}
+
+func TestNodeIDBounds(t *testing.T) {
+	gen, err := NewGenerator(Config{Version: Version0, NodeID: 7})
+	if err != nil {
+		t.Fatalf("Failed to create generator: %v", err)
+	}
+
+	from := time.Now().Add(-time.Second)
+	id, err := gen.NextID()
+	if err != nil {
+		t.Fatalf("Failed to generate ID: %v", err)
+	}
+	to := time.Now().Add(time.Second)
+
+	lo, hi, err := NodeIDBounds(Version0, from, to, 7)
+	if err != nil {
+		t.Fatalf("NodeIDBounds failed: %v", err)
+	}
+	if id < lo || id > hi {
+		t.Errorf("ID %d outside bounds [%d, %d]", id, lo, hi)
+	}
+
+	if _, _, err := NodeIDBounds(Version0, from, to, 256); !errors.Is(err, ErrInvalidNodeID) {
+		t.Errorf("Expected ErrInvalidNodeID, got %v", err)
+	}
+}
+
+func TestIDBounds_InvalidRange(t *testing.T) {
+	epoch := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		from, to time.Time
+	}{
+		{name: "empty range", from: epoch.Add(time.Hour), to: epoch.Add(time.Hour)},
+		{name: "reversed range", from: epoch.Add(time.Hour), to: epoch},
+		{name: "before epoch", from: epoch.Add(-2 * time.Hour), to: epoch.Add(-time.Hour)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, err := IDBounds(Version0, tt.from, tt.to); !errors.Is(err, ErrInvalidRange) {
+				t.Errorf("Expected ErrInvalidRange, got %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,132 @@
+package snowflake
+
+import (
+	"fmt"
+	"time"
+)
+
+// Builder stages, in the order fields must be declared
+const (
+	stageStart = iota
+	stageVersion
+	stageTime
+	stageFields
+	stageNode
+	stageSequence
+	stageNodeLast
+)
+
+// LayoutBuilder declares a custom layout field by field, most significant
+// first:
+//
+//	layout, err := snowflake.NewLayoutBuilder().
+//		Time(41, time.Millisecond, epoch).
+//		Field("region", 4).
+//		Field("node", 8).
+//		Sequence(10).
+//		Build()
+//
+// Fields must be declared in layout order: an optional Version, Time, any
+// custom fields, an optional "node" field, then Sequence. The node field
+// may instead follow Sequence, which sets NodeLast. Without Version the
+// layout is versionless. The first misuse is reported by Build.
+type LayoutBuilder struct {
+	layout VersionLayout
+	stage  int
+	err    error
+}
+
+// NewLayoutBuilder starts an empty layout declaration
+func NewLayoutBuilder() *LayoutBuilder {
+	return &LayoutBuilder{}
+}
+
+// Version declares the version field and the version number it carries
+func (b *LayoutBuilder) Version(v Version, bits uint8) *LayoutBuilder {
+	if b.advance("version", stageVersion) {
+		b.layout.Version = v
+		b.layout.VersionBits = bits
+	}
+	return b
+}
+
+// Time declares the timestamp field, its unit and epoch
+func (b *LayoutBuilder) Time(bits uint8, unit time.Duration, epoch time.Time) *LayoutBuilder {
+	if b.advance("time", stageTime) {
+		b.layout.TimeBits = bits
+		b.layout.TimeUnit = unit
+		b.layout.Epoch = epoch
+	}
+	return b
+}
+
+// Field declares a named field. The name "node" declares the node field;
+// any other name declares a custom field, which must come before it.
+func (b *LayoutBuilder) Field(name string, bits uint8) *LayoutBuilder {
+	if name == "node" {
+		stage := stageNode
+		if b.stage == stageSequence && b.layout.NodeBits == 0 {
+			stage = stageNodeLast
+		}
+		if b.advance("node", stage) {
+			b.layout.NodeBits = bits
+			b.layout.NodeLast = stage == stageNodeLast
+		}
+		return b
+	}
+
+	if b.stage < stageTime && b.err == nil {
+		b.err = fmt.Errorf("%w: field %q declared before time", ErrInvalidLayout, name)
+	}
+	if b.advance(name, stageFields) {
+		b.layout.Fields = append(b.layout.Fields, Field{Name: name, Bits: bits})
+	}
+	return b
+}
+
+// Sequence declares the sequence field, which is least significant unless
+// the node field follows it
+func (b *LayoutBuilder) Sequence(bits uint8) *LayoutBuilder {
+	if b.stage < stageTime && b.err == nil {
+		b.err = fmt.Errorf("%w: sequence declared before time", ErrInvalidLayout)
+	}
+	if b.advance("sequence", stageSequence) {
+		b.layout.SequenceBits = bits
+	}
+	return b
+}
+
+// Build validates the declaration and returns the layout
+func (b *LayoutBuilder) Build() (*VersionLayout, error) {
+	if b.err != nil {
+		return nil, b.err
+	}
+	if b.stage != stageSequence && b.stage != stageNodeLast {
+		return nil, fmt.Errorf("%w: layout needs time and sequence fields", ErrInvalidLayout)
+	}
+
+	layout := b.layout
+	layout.Fields = append([]Field(nil), b.layout.Fields...)
+	layout.MaxTimestamp = mask(layout.TimeBits)
+	layout.MaxNodeID = mask(layout.NodeBits)
+	layout.MaxSequence = mask(layout.SequenceBits)
+
+	if err := layout.validate(); err != nil {
+		return nil, err
+	}
+	return &layout, nil
+}
+
+// advance moves the builder to stage, recording an error if name is
+// declared out of order. Custom fields may repeat their stage.
+func (b *LayoutBuilder) advance(name string, stage int) bool {
+	if b.err != nil {
+		return false
+	}
+	if stage < b.stage || (stage == b.stage && stage != stageFields) {
+		b.err = fmt.Errorf("%w: field %q declared out of order", ErrInvalidLayout, name)
+		return false
+	}
+	b.stage = stage
+	return true
+}
@@ -0,0 +1,195 @@
+package snowflake
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestLayoutBuilder(t *testing.T) {
+	layout, err := NewLayoutBuilder().
+		Time(41, time.Millisecond, EpochY2020).
+		Field("region", 4).
+		Field("node", 8).
+		Sequence(10).
+		Build()
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+
+	if layout.VersionBits != 0 || layout.TimeBits != 41 || layout.NodeBits != 8 || layout.SequenceBits != 10 {
+		t.Errorf("Unexpected layout widths: %+v", layout)
+	}
+	if len(layout.Fields) != 1 || layout.Fields[0] != (Field{Name: "region", Bits: 4}) {
+		t.Errorf("Unexpected custom fields: %+v", layout.Fields)
+	}
+	if layout.MaxNodeID != 255 || layout.MaxSequence != 1023 || layout.MaxTimestamp != (1<<41)-1 {
+		t.Errorf("Unexpected maximums: %+v", layout)
+	}
+
+	gen, err := NewGenerator(Config{Layout: layout, NodeID: 42, Fields: map[string]uint64{"region": 11}})
+	if err != nil {
+		t.Fatalf("Failed to create generator: %v", err)
+	}
+
+	id, err := gen.NextID()
+	if err != nil {
+		t.Fatalf("Failed to generate ID: %v", err)
+	}
+	if id>>63 != 0 {
+		t.Errorf("Expected unused top bit to be zero in %d", id)
+	}
+
+	fields, err := DecodeFields(id, layout)
+	if err != nil {
+		t.Fatalf("DecodeFields failed: %v", err)
+	}
+	if fields["region"] != 11 || fields["node"] != 42 {
+		t.Errorf("Unexpected field values: %v", fields)
+	}
+	if _, ok := fields["version"]; ok {
+		t.Error("Versionless layout should not report a version field")
+	}
+
+	decoded, err := DecodeWithLayout(id, layout)
+	if err != nil {
+		t.Fatalf("DecodeWithLayout failed: %v", err)
+	}
+	if region, ok := decoded.Field("region"); !ok || region != 11 {
+		t.Errorf("Expected region 11, got %d (present: %v)", region, ok)
+	}
+	if decoded.NodeID != 42 {
+		t.Errorf("Expected typed node ID 42, got %d", decoded.NodeID)
+	}
+}
+
+func TestLayoutBuilder_NodeLast(t *testing.T) {
+	layout, err := NewLayoutBuilder().
+		Time(39, 10*time.Millisecond, EpochSonyflake2014).
+		Sequence(8).
+		Field("node", 16).
+		Build()
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+	if !layout.NodeLast || layout.NodeBits != 16 {
+		t.Fatalf("Expected a 16-bit node field after the sequence: %+v", layout)
+	}
+
+	shifts := layout.Shifts()
+	if shifts.Time != 24 || shifts.Sequence != 16 || shifts.Node != 0 {
+		t.Errorf("Unexpected shifts: %+v", shifts)
+	}
+
+	id, err := EncodeComponents(layout, 5, 0x1234, 3)
+	if err != nil {
+		t.Fatalf("EncodeComponents failed: %v", err)
+	}
+	if want := uint64(5<<24 | 3<<16 | 0x1234); id != want {
+		t.Errorf("Expected %d, got %d", want, id)
+	}
+}
+
+func TestLayoutBuilder_Versioned(t *testing.T) {
+	layout, err := NewLayoutBuilder().
+		Version(5, 3).
+		Time(40, time.Millisecond, EpochY2026).
+		Field("env", 2).
+		Field("node", 9).
+		Sequence(10).
+		Build()
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+
+	gen, err := NewGenerator(Config{Layout: layout, NodeID: 300, Fields: map[string]uint64{"env": 2}})
+	if err != nil {
+		t.Fatalf("Failed to create generator: %v", err)
+	}
+
+	id, err := gen.NextID()
+	if err != nil {
+		t.Fatalf("Failed to generate ID: %v", err)
+	}
+
+	fields, err := DecodeFields(id, layout)
+	if err != nil {
+		t.Fatalf("DecodeFields failed: %v", err)
+	}
+	if fields["version"] != 5 || fields["env"] != 2 || fields["node"] != 300 {
+		t.Errorf("Unexpected field values: %v", fields)
+	}
+}
+
+func TestLayoutBuilder_Errors(t *testing.T) {
+	tests := []struct {
+		name  string
+		build func() (*VersionLayout, error)
+	}{
+		{
+			name: "field after node",
+			build: func() (*VersionLayout, error) {
+				return NewLayoutBuilder().Time(41, time.Millisecond, EpochY2020).
+					Field("node", 8).Field("region", 4).Sequence(10).Build()
+			},
+		},
+		{
+			name: "node before and after sequence",
+			build: func() (*VersionLayout, error) {
+				return NewLayoutBuilder().Time(41, time.Millisecond, EpochY2020).
+					Field("node", 8).Sequence(10).Field("node", 4).Build()
+			},
+		},
+		{
+			name: "missing sequence",
+			build: func() (*VersionLayout, error) {
+				return NewLayoutBuilder().Time(41, time.Millisecond, EpochY2020).Field("node", 8).Build()
+			},
+		},
+		{
+			name: "field before time",
+			build: func() (*VersionLayout, error) {
+				return NewLayoutBuilder().Field("region", 4).Time(41, time.Millisecond, EpochY2020).Sequence(10).Build()
+			},
+		},
+		{
+			name: "reserved name",
+			build: func() (*VersionLayout, error) {
+				return NewLayoutBuilder().Time(41, time.Millisecond, EpochY2020).Field("time", 4).Sequence(10).Build()
+			},
+		},
+		{
+			name: "too wide",
+			build: func() (*VersionLayout, error) {
+				return NewLayoutBuilder().Time(50, time.Millisecond, EpochY2020).Field("region", 8).Sequence(10).Build()
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := tt.build(); !errors.Is(err, ErrInvalidLayout) {
+				t.Errorf("Expected ErrInvalidLayout, got %v", err)
+			}
+		})
+	}
+}
+
+func TestNewGenerator_InvalidFields(t *testing.T) {
+	layout, err := NewLayoutBuilder().Time(41, time.Millisecond, EpochY2020).
+		Field("region", 4).Field("node", 8).Sequence(10).Build()
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+
+	for name, fields := range map[string]map[string]uint64{
+		"unknown field":  {"zone": 1},
+		"value too wide": {"region": 16},
+	} {
+		t.Run(name, func(t *testing.T) {
+			if _, err := NewGenerator(Config{Layout: layout, Fields: fields}); !errors.Is(err, ErrInvalidLayout) {
+				t.Errorf("Expected ErrInvalidLayout, got %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,57 @@
+package snowflake
+
+import "fmt"
+
+// ChildField is the name of the optional custom field (up to 16 bits)
+// holding a child counter. Generators always leave it zero, so every
+// issued ID can parent up to 2^bits-1 derived IDs, e.g. line items under
+// an order, without a separate key column.
+const ChildField = "child"
+
+// maxChildBits is the widest child field, matching DeriveChild's counter
+const maxChildBits = 16
+
+// DeriveChild returns the n-th child of parent, for n from 1 to the
+// largest value the layout's ChildField holds. Children share every other
+// component with the parent and never collide with generated IDs.
+func DeriveChild(parent ID, n uint16) (ID, error) {
+	shift, bits, err := childField(uint64(parent))
+	if err != nil {
+		return 0, err
+	}
+	if uint64(parent)&(mask(bits)<<shift) != 0 {
+		return 0, fmt.Errorf("%w: %d is itself a child", ErrInvalidID, parent)
+	}
+	if n == 0 || uint64(n) > mask(bits) {
+		return 0, fmt.Errorf("%w: child %d (max: %d)", ErrComponentRange, n, mask(bits))
+	}
+	return parent | ID(uint64(n)<<shift), nil
+}
+
+// ParentOf returns the parent and child number of a derived ID. ok is
+// false for IDs that are not children, including IDs whose layout has no
+// ChildField.
+func ParentOf(id ID) (parent ID, n uint16, ok bool) {
+	shift, bits, err := childField(uint64(id))
+	if err != nil {
+		return 0, 0, false
+	}
+	n = uint16((uint64(id) >> shift) & mask(bits))
+	if n == 0 {
+		return 0, 0, false
+	}
+	return id &^ ID(mask(bits)<<shift), n, true
+}
+
+// childField returns the position of the child field in id's layout
+func childField(id uint64) (shift, bits uint8, err error) {
+	layout, err := extractVersion(id)
+	if err != nil {
+		return 0, 0, err
+	}
+	shift, bits, ok := layout.field(ChildField)
+	if !ok {
+		return 0, 0, fmt.Errorf("%w: version %d has no %s field", ErrInvalidLayout, layout.Version, ChildField)
+	}
+	return shift, bits, nil
+}
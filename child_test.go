@@ -0,0 +1,81 @@
+package snowflake
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestDeriveChild(t *testing.T) {
+	layout, err := NewLayoutBuilder().
+		Version(6, 3).
+		Time(41, time.Millisecond, EpochY2026).
+		Field(ChildField, 4).
+		Field("node", 6).
+		Sequence(10).
+		Build()
+	if err != nil {
+		t.Fatalf("Failed to build layout: %v", err)
+	}
+	withTestLayout(t, layout)
+
+	gen, err := NewGenerator(Config{Version: 6, NodeID: 3})
+	if err != nil {
+		t.Fatalf("Failed to create generator: %v", err)
+	}
+	raw, err := gen.NextID()
+	if err != nil {
+		t.Fatalf("NextID failed: %v", err)
+	}
+	parent := ID(raw)
+
+	if _, _, ok := ParentOf(parent); ok {
+		t.Error("Issued ID reported as a child")
+	}
+
+	seen := map[ID]bool{parent: true}
+	for n := uint16(1); n <= 15; n++ {
+		child, err := DeriveChild(parent, n)
+		if err != nil {
+			t.Fatalf("Failed to derive child %d: %v", n, err)
+		}
+		if seen[child] {
+			t.Fatalf("Child %d collides: %d", n, child)
+		}
+		seen[child] = true
+
+		got, gotN, ok := ParentOf(child)
+		if !ok || got != parent || gotN != n {
+			t.Errorf("ParentOf(%d) = %d, %d, %t; want %d, %d", child, got, gotN, ok, parent, n)
+		}
+	}
+
+	if _, err := DeriveChild(parent, 16); !errors.Is(err, ErrComponentRange) {
+		t.Errorf("Expected ErrComponentRange for child 16, got %v", err)
+	}
+	if _, err := DeriveChild(parent, 0); !errors.Is(err, ErrComponentRange) {
+		t.Errorf("Expected ErrComponentRange for child 0, got %v", err)
+	}
+	child, err := DeriveChild(parent, 1)
+	if err != nil {
+		t.Fatalf("Failed to derive child: %v", err)
+	}
+	if _, err := DeriveChild(child, 1); !errors.Is(err, ErrInvalidID) {
+		t.Errorf("Expected ErrInvalidID for grandchild, got %v", err)
+	}
+}
+
+func TestDeriveChild_NoField(t *testing.T) {
+	gen, err := NewGenerator(Config{Version: Version0})
+	if err != nil {
+		t.Fatalf("Failed to create generator: %v", err)
+	}
+	id, err := gen.NextID()
+	if err != nil {
+		t.Fatalf("NextID failed: %v", err)
+	}
+
+	if _, err := DeriveChild(ID(id), 1); !errors.Is(err, ErrInvalidLayout) {
+		t.Errorf("Expected ErrInvalidLayout, got %v", err)
+	}
+}
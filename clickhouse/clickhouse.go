@@ -0,0 +1,178 @@
+// Package clickhouse maps snowflake IDs and their components to ClickHouse
+// column types, with a column builder for clickhouse-go batches:
+//
+//	b, err := clickhouse.NewBuilder(&layout)
+//	err = b.Append(ids...)
+//
+//	batch, err := conn.PrepareBatch(ctx, "INSERT INTO events (id, time, node, sequence)")
+//	for i, col := range b.Columns() {
+//		err = batch.Column(i).Append(col.Values)
+//	}
+//	err = batch.Send()
+//
+// IDs are UInt64, times are DateTime64 in UTC at the precision of the
+// layout's time unit, and the node, sequence and custom fields use the
+// narrowest UInt type that holds them. The package has no driver
+// dependency.
+package clickhouse
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/samarthasthan/snowflake"
+)
+
+// Column is a named column of decoded values. Values is a []uint8,
+// []uint16, []uint32, []uint64 or []time.Time matching Type.
+type Column struct {
+	Name   string
+	Type   string
+	Values any
+}
+
+// Schema returns the column definitions for a table of IDs decoded with
+// layout, in the order Builder.Columns returns them, e.g.
+// "id UInt64, time DateTime64(3, 'UTC'), node UInt8, sequence UInt8"
+func Schema(layout *snowflake.VersionLayout) string {
+	defs := []string{
+		"id UInt64",
+		"time " + DateTime64Type(layout.TimeUnit),
+	}
+	for _, f := range fieldsOf(layout) {
+		defs = append(defs, f.Name+" "+UIntType(f.Bits))
+	}
+	return strings.Join(defs, ", ")
+}
+
+// DateTime64Type returns the UTC DateTime64 type whose precision resolves
+// unit, e.g. DateTime64(3, 'UTC') for milliseconds
+func DateTime64Type(unit time.Duration) string {
+	precision := 0
+	for step := time.Second; step > unit && precision < 9; step /= 10 {
+		precision++
+	}
+	return fmt.Sprintf("DateTime64(%d, 'UTC')", precision)
+}
+
+// UIntType returns the narrowest UInt type holding bits bits
+func UIntType(bits uint8) string {
+	switch {
+	case bits <= 8:
+		return "UInt8"
+	case bits <= 16:
+		return "UInt16"
+	case bits <= 32:
+		return "UInt32"
+	default:
+		return "UInt64"
+	}
+}
+
+// Builder accumulates decoded IDs column by column
+type Builder struct {
+	layout snowflake.VersionLayout
+	fields []snowflake.Field
+	ids    []uint64
+	times  []time.Time
+	values [][]uint64 // node, sequence and custom fields, in fields order
+}
+
+// NewBuilder returns a builder decoding IDs with layout
+func NewBuilder(layout *snowflake.VersionLayout) (*Builder, error) {
+	// Decoding no IDs validates the layout
+	if _, err := snowflake.DecodeColumns(nil, layout); err != nil {
+		return nil, err
+	}
+
+	fields := fieldsOf(layout)
+	return &Builder{
+		layout: *layout,
+		fields: fields,
+		values: make([][]uint64, len(fields)),
+	}, nil
+}
+
+// Append decodes ids and adds them to the columns. If any ID does not
+// belong to the layout, nothing is added.
+func (b *Builder) Append(ids ...uint64) error {
+	cols, err := snowflake.DecodeColumns(ids, &b.layout)
+	if err != nil {
+		return err
+	}
+
+	b.ids = append(b.ids, ids...)
+	perSecond := int64(time.Second / cols.TimeUnit)
+	for _, v := range cols.Time {
+		b.times = append(b.times, time.Unix(v/perSecond, v%perSecond*int64(cols.TimeUnit)).UTC())
+	}
+	for i, f := range b.fields {
+		switch f.Name {
+		case "node":
+			b.values[i] = append(b.values[i], cols.Node...)
+		case "sequence":
+			b.values[i] = append(b.values[i], cols.Sequence...)
+		default:
+			b.values[i] = append(b.values[i], cols.Fields[f.Name]...)
+		}
+	}
+	return nil
+}
+
+// Len returns the number of rows appended
+func (b *Builder) Len() int {
+	return len(b.ids)
+}
+
+// Columns returns the accumulated columns in Schema order. The slices are
+// shared with the builder until Reset.
+func (b *Builder) Columns() []Column {
+	cols := []Column{
+		{Name: "id", Type: "UInt64", Values: b.ids},
+		{Name: "time", Type: DateTime64Type(b.layout.TimeUnit), Values: b.times},
+	}
+	for i, f := range b.fields {
+		cols = append(cols, Column{Name: f.Name, Type: UIntType(f.Bits), Values: narrow(b.values[i], f.Bits)})
+	}
+	return cols
+}
+
+// Reset empties the builder for the next batch
+func (b *Builder) Reset() {
+	b.ids = nil
+	b.times = nil
+	b.values = make([][]uint64, len(b.fields))
+}
+
+// fieldsOf lists the component columns after id and time: custom fields
+// in layout order, then node and sequence
+func fieldsOf(layout *snowflake.VersionLayout) []snowflake.Field {
+	fields := append([]snowflake.Field(nil), layout.Fields...)
+	return append(fields,
+		snowflake.Field{Name: "node", Bits: layout.NodeBits},
+		snowflake.Field{Name: "sequence", Bits: layout.SequenceBits},
+	)
+}
+
+// narrow converts values to the slice type of UIntType(bits)
+func narrow(values []uint64, bits uint8) any {
+	switch UIntType(bits) {
+	case "UInt8":
+		return convert[uint8](values)
+	case "UInt16":
+		return convert[uint16](values)
+	case "UInt32":
+		return convert[uint32](values)
+	default:
+		return values
+	}
+}
+
+func convert[T uint8 | uint16 | uint32](values []uint64) []T {
+	out := make([]T, len(values))
+	for i, v := range values {
+		out[i] = T(v)
+	}
+	return out
+}
@@ -0,0 +1,110 @@
+package clickhouse
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/samarthasthan/snowflake"
+)
+
+// exampleID is the Version0 ID for node 7 at 2026-03-01T00:00:00Z,
+// sequence 1
+const exampleID = 334076313601793
+
+func TestSchema(t *testing.T) {
+	v0, _ := snowflake.LookupLayout(snowflake.Version0)
+	want := "id UInt64, time DateTime64(3, 'UTC'), node UInt8, sequence UInt8"
+	if got := Schema(&v0); got != want {
+		t.Errorf("Schema = %q, want %q", got, want)
+	}
+
+	seconds, _ := snowflake.LookupLayout(snowflake.Version3)
+	want = "id UInt64, time DateTime64(0, 'UTC'), node UInt16, sequence UInt16"
+	if got := Schema(&seconds); got != want {
+		t.Errorf("Schema = %q, want %q", got, want)
+	}
+}
+
+func TestDateTime64Type(t *testing.T) {
+	tests := []struct {
+		unit time.Duration
+		want string
+	}{
+		{time.Second, "DateTime64(0, 'UTC')"},
+		{10 * time.Millisecond, "DateTime64(2, 'UTC')"},
+		{time.Millisecond, "DateTime64(3, 'UTC')"},
+		{time.Microsecond, "DateTime64(6, 'UTC')"},
+		{time.Nanosecond, "DateTime64(9, 'UTC')"},
+	}
+	for _, tt := range tests {
+		if got := DateTime64Type(tt.unit); got != tt.want {
+			t.Errorf("DateTime64Type(%v) = %s, want %s", tt.unit, got, tt.want)
+		}
+	}
+}
+
+func TestBuilder(t *testing.T) {
+	layout, err := snowflake.NewLayoutBuilder().
+		Time(41, time.Millisecond, snowflake.EpochY2020).
+		Field("region", 4).
+		Field("node", 10).
+		Sequence(9).
+		Build()
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+
+	b, err := NewBuilder(layout)
+	if err != nil {
+		t.Fatalf("NewBuilder failed: %v", err)
+	}
+	id, _ := snowflake.EncodeComponents(layout, 1000, 700, 3)
+	id |= 5 << layout.Shifts().Fields["region"]
+	if err := b.Append(id, id+1); err != nil {
+		t.Fatalf("Append failed: %v", err)
+	}
+
+	cols := b.Columns()
+	var names []string
+	for _, col := range cols {
+		names = append(names, col.Name)
+	}
+	if want := []string{"id", "time", "region", "node", "sequence"}; !reflect.DeepEqual(names, want) {
+		t.Fatalf("Columns = %v, want %v", names, want)
+	}
+
+	want := []any{
+		[]uint64{id, id + 1},
+		[]time.Time{snowflake.EpochY2020.Add(time.Second), snowflake.EpochY2020.Add(time.Second)},
+		[]uint8{5, 5},
+		[]uint16{700, 700},
+		[]uint16{3, 4},
+	}
+	for i, col := range cols {
+		if !reflect.DeepEqual(col.Values, want[i]) {
+			t.Errorf("Column %s = %v, want %v", col.Name, col.Values, want[i])
+		}
+	}
+
+	b.Reset()
+	if b.Len() != 0 {
+		t.Errorf("Expected an empty builder after Reset, got %d rows", b.Len())
+	}
+}
+
+func TestBuilder_WrongVersion(t *testing.T) {
+	v0, _ := snowflake.LookupLayout(snowflake.Version0)
+	b, err := NewBuilder(&v0)
+	if err != nil {
+		t.Fatalf("NewBuilder failed: %v", err)
+	}
+
+	if err := b.Append(exampleID, 7<<61); !errors.Is(err, snowflake.ErrInvalidVersion) {
+		t.Fatalf("Expected ErrInvalidVersion, got %v", err)
+	}
+	if b.Len() != 0 {
+		t.Errorf("Expected no rows after a failed Append, got %d", b.Len())
+	}
+}
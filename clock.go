@@ -0,0 +1,98 @@
+package snowflake
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ErrClockQuorum is returned when too few time sources agree on the time
+var ErrClockQuorum = errors.New("clock quorum not reached")
+
+// Clock is a source of wall time for a generator. Reads may fail, in which
+// case the generator issues no ID and returns the error.
+type Clock interface {
+	Now() (time.Time, error)
+}
+
+// ClockFunc adapts a function to the Clock interface
+type ClockFunc func() (time.Time, error)
+
+// Now calls f
+func (f ClockFunc) Now() (time.Time, error) {
+	return f()
+}
+
+// SystemClock reads the operating system's wall clock
+var SystemClock Clock = ClockFunc(func() (time.Time, error) {
+	return time.Now(), nil
+})
+
+// StepClock is a fake Clock that returns start on its first read and
+// advances by a fixed step on every read after, so a generator using it
+// issues the same IDs on every run. It is safe for concurrent use.
+type StepClock struct {
+	mu   sync.Mutex
+	next time.Time
+	step time.Duration
+}
+
+// NewStepClock returns a StepClock starting at start
+func NewStepClock(start time.Time, step time.Duration) *StepClock {
+	return &StepClock{next: start, step: step}
+}
+
+// Now implements Clock
+func (c *StepClock) Now() (time.Time, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.next
+	c.next = c.next.Add(c.step)
+	return now, nil
+}
+
+// quorumClock reports the time only when a majority of its sources agree
+type quorumClock struct {
+	clocks    []Clock
+	tolerance time.Duration
+}
+
+// NewQuorumClock returns an experimental Clock that reads every source and
+// succeeds only when a strict majority of them agree within tolerance,
+// returning the median of the agreeing readings. It guards against a
+// single bad source, such as a VM clock that jumps after live migration.
+// Sources that fail to read count as disagreeing.
+func NewQuorumClock(tolerance time.Duration, clocks ...Clock) Clock {
+	return &quorumClock{clocks: clocks, tolerance: tolerance}
+}
+
+// Now implements Clock
+func (q *quorumClock) Now() (time.Time, error) {
+	readings := make([]time.Time, 0, len(q.clocks))
+	for _, c := range q.clocks {
+		if t, err := c.Now(); err == nil {
+			readings = append(readings, t)
+		}
+	}
+	sort.Slice(readings, func(i, j int) bool { return readings[i].Before(readings[j]) })
+
+	// Find the largest window of readings spanning at most tolerance
+	best, bestStart := 0, 0
+	for start, end := 0, 0; end < len(readings); end++ {
+		for readings[end].Sub(readings[start]) > q.tolerance {
+			start++
+		}
+		if n := end - start + 1; n > best {
+			best, bestStart = n, start
+		}
+	}
+
+	if need := len(q.clocks)/2 + 1; best < need {
+		return time.Time{}, fmt.Errorf("%w: %d of %d clocks agree within %s (need %d)",
+			ErrClockQuorum, best, len(q.clocks), q.tolerance, need)
+	}
+	return readings[bestStart+best/2], nil
+}
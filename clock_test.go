@@ -0,0 +1,119 @@
+package snowflake
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func fixedClock(t time.Time) Clock {
+	return ClockFunc(func() (time.Time, error) { return t, nil })
+}
+
+var errClockRead = errors.New("clock read failed")
+
+func failingClock() Clock {
+	return ClockFunc(func() (time.Time, error) { return time.Time{}, errClockRead })
+}
+
+func TestQuorumClock(t *testing.T) {
+	base := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name   string
+		clocks []Clock
+		want   time.Time
+		ok     bool
+	}{
+		{
+			name:   "all agree",
+			clocks: []Clock{fixedClock(base), fixedClock(base.Add(time.Millisecond)), fixedClock(base.Add(2 * time.Millisecond))},
+			want:   base.Add(time.Millisecond),
+			ok:     true,
+		},
+		{
+			name:   "one outlier",
+			clocks: []Clock{fixedClock(base), fixedClock(base.Add(time.Hour)), fixedClock(base.Add(time.Millisecond))},
+			want:   base.Add(time.Millisecond),
+			ok:     true,
+		},
+		{
+			name:   "one failing",
+			clocks: []Clock{fixedClock(base), failingClock(), fixedClock(base)},
+			want:   base,
+			ok:     true,
+		},
+		{
+			name:   "no majority",
+			clocks: []Clock{fixedClock(base), fixedClock(base.Add(time.Hour)), failingClock()},
+		},
+		{
+			name:   "two sources disagree",
+			clocks: []Clock{fixedClock(base), fixedClock(base.Add(time.Second))},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewQuorumClock(5*time.Millisecond, tt.clocks...).Now()
+			if !tt.ok {
+				if !errors.Is(err, ErrClockQuorum) {
+					t.Errorf("Expected ErrClockQuorum, got %v", err)
+				}
+				return
+			}
+			if err != nil || !got.Equal(tt.want) {
+				t.Errorf("Expected %s, got %s (%v)", tt.want, got, err)
+			}
+		})
+	}
+}
+
+func TestGenerator_Clock(t *testing.T) {
+	at := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	gen, err := NewGenerator(Config{Version: Version0, NodeID: 3, Clock: fixedClock(at)})
+	if err != nil {
+		t.Fatalf("Failed to create generator: %v", err)
+	}
+
+	id, err := gen.NextID()
+	if err != nil {
+		t.Fatalf("Failed to generate ID: %v", err)
+	}
+	decoded, _ := Decode(id)
+	if !decoded.Time.Equal(at) {
+		t.Errorf("Expected time %s from clock, got %s", at, decoded.Time)
+	}
+
+	if _, err := NewGenerator(Config{Version: Version0, Clock: failingClock()}); !errors.Is(err, errClockRead) {
+		t.Errorf("Expected clock error from NewGenerator, got %v", err)
+	}
+
+	var fail bool
+	flaky := ClockFunc(func() (time.Time, error) {
+		if fail {
+			return time.Time{}, errClockRead
+		}
+		return at, nil
+	})
+	gen, _ = NewGenerator(Config{Version: Version0, Clock: flaky})
+	fail = true
+	if _, err := gen.NextID(); !errors.Is(err, errClockRead) {
+		t.Errorf("Expected clock error from NextID, got %v", err)
+	}
+}
+
+func TestStepClock(t *testing.T) {
+	start := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	clock := NewStepClock(start, time.Millisecond)
+
+	for i := range 3 {
+		now, err := clock.Now()
+		if err != nil {
+			t.Fatalf("Now failed: %v", err)
+		}
+		if want := start.Add(time.Duration(i) * time.Millisecond); !now.Equal(want) {
+			t.Errorf("Read %d: expected %s, got %s", i, want, now)
+		}
+	}
+}
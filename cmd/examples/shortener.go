@@ -0,0 +1,165 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/samarthasthan/snowflake"
+)
+
+// link is a row of the links table
+type link struct {
+	ID      string    `json:"id"` // decimal string: JSON numbers lose precision above 2^53
+	Code    string    `json:"code"`
+	URL     string    `json:"url"`
+	Created time.Time `json:"created"`
+}
+
+// shortener issues short codes for URLs. The code is the link's ID in
+// base62, so it needs no separate unique index and sorts by creation time.
+type shortener struct {
+	gen   *snowflake.Generator
+	v     snowflake.Version
+	links *table[link]
+}
+
+// runShortener serves the URL shortener:
+//
+//	POST /links          url=<target>  create a link, returns JSON
+//	GET  /links?since=1h               list links created in the window
+//	GET  /<code>                       redirect to the target
+func runShortener(args []string) error {
+	fs := flag.NewFlagSet("shortener", flag.ContinueOnError)
+	addr := fs.String("addr", "localhost:8080", "listen address")
+	node := fs.Uint64("node", 0, "node ID of this instance")
+	v := snowflake.Version0
+	fs.Var(&v, "version", "layout version (number, vN or name)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	gen, err := snowflake.NewGenerator(snowflake.Config{Version: v, NodeID: *node})
+	if err != nil {
+		return err
+	}
+	s := &shortener{gen: gen, v: v, links: newTable[link]("links")}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /links", s.create)
+	mux.HandleFunc("GET /links", s.list)
+	mux.HandleFunc("GET /{code}", s.redirect)
+
+	log.Printf("shortener listening on http://%s", *addr)
+	return http.ListenAndServe(*addr, mux)
+}
+
+func (s *shortener) create(w http.ResponseWriter, r *http.Request) {
+	target, err := url.Parse(r.FormValue("url"))
+	if err != nil || (target.Scheme != "http" && target.Scheme != "https") {
+		http.Error(w, "url must be an absolute http(s) URL", http.StatusBadRequest)
+		return
+	}
+
+	id, err := s.gen.NextID()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusServiceUnavailable)
+		return
+	}
+	decoded, err := snowflake.Decode(id)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	row := link{ID: snowflake.ID(id).String(), Code: base62Encode(id), URL: target.String(), Created: decoded.Time}
+	s.links.insert(id, row)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	json.NewEncoder(w).Encode(row)
+}
+
+func (s *shortener) redirect(w http.ResponseWriter, r *http.Request) {
+	id, err := base62Decode(r.PathValue("code"))
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+	row, ok := s.links.get(id)
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	http.Redirect(w, r, row.URL, http.StatusFound)
+}
+
+// list returns the links created in the last ?since= window. The window is
+// translated to an ID range, so the scan uses the primary key alone.
+func (s *shortener) list(w http.ResponseWriter, r *http.Request) {
+	since := time.Hour
+	if q := r.URL.Query().Get("since"); q != "" {
+		d, err := time.ParseDuration(q)
+		if err != nil || d <= 0 {
+			http.Error(w, "since must be a positive duration", http.StatusBadRequest)
+			return
+		}
+		since = d
+	}
+
+	now := time.Now()
+	lo, hi, err := snowflake.IDBounds(s.v, now.Add(-since), now.Add(time.Second))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(struct {
+		SQL   string `json:"sql"`
+		Links []link `json:"links"`
+	}{SQL: s.links.rangeSQL(lo, hi), Links: s.links.scan(lo, hi)})
+}
+
+const base62Alphabet = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+
+// base62Encode returns id in base62. Codes of equal length sort like IDs.
+func base62Encode(id uint64) string {
+	if id == 0 {
+		return "0"
+	}
+	var buf [11]byte
+	i := len(buf)
+	for id > 0 {
+		i--
+		buf[i] = base62Alphabet[id%62]
+		id /= 62
+	}
+	return string(buf[i:])
+}
+
+// base62Decode parses a base62 code
+func base62Decode(code string) (uint64, error) {
+	if code == "" || len(code) > 11 {
+		return 0, errors.New("invalid code length")
+	}
+	var id uint64
+	for _, c := range []byte(code) {
+		digit := strings.IndexByte(base62Alphabet, c)
+		if digit < 0 {
+			return 0, fmt.Errorf("invalid character %q", c)
+		}
+		next := id*62 + uint64(digit)
+		if next/62 != id {
+			return 0, errors.New("code overflows 64 bits")
+		}
+		id = next
+	}
+	return id, nil
+}
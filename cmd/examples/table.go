@@ -0,0 +1,66 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// table is an in-memory stand-in for a database table with a snowflake ID
+// primary key. Rows are kept sorted by ID, as a clustered index keeps
+// them, so time-range queries become ID-range scans.
+type table[T any] struct {
+	mu   sync.RWMutex
+	name string
+	ids  []uint64
+	rows []T
+}
+
+func newTable[T any](name string) *table[T] {
+	return &table[T]{name: name}
+}
+
+// insert adds a row. IDs from concurrent callers may arrive slightly out of
+// order, so the row is placed rather than appended.
+func (t *table[T]) insert(id uint64, row T) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	i := sort.Search(len(t.ids), func(i int) bool { return t.ids[i] >= id })
+	var zero T
+	t.ids = append(t.ids, 0)
+	t.rows = append(t.rows, zero)
+	copy(t.ids[i+1:], t.ids[i:])
+	copy(t.rows[i+1:], t.rows[i:])
+	t.ids[i], t.rows[i] = id, row
+}
+
+// get looks up a row by primary key:
+//
+//	SELECT * FROM <table> WHERE id = $1
+func (t *table[T]) get(id uint64) (T, bool) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	i := sort.Search(len(t.ids), func(i int) bool { return t.ids[i] >= id })
+	if i < len(t.ids) && t.ids[i] == id {
+		return t.rows[i], true
+	}
+	var zero T
+	return zero, false
+}
+
+// scan returns the rows with IDs in [lo, hi] in ID order
+func (t *table[T]) scan(lo, hi uint64) []T {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	from := sort.Search(len(t.ids), func(i int) bool { return t.ids[i] >= lo })
+	to := sort.Search(len(t.ids), func(i int) bool { return t.ids[i] > hi })
+	return append([]T(nil), t.rows[from:to]...)
+}
+
+// rangeSQL returns the query a real table would run for scan(lo, hi)
+func (t *table[T]) rangeSQL(lo, hi uint64) string {
+	return fmt.Sprintf("SELECT * FROM %s WHERE id BETWEEN %d AND %d ORDER BY id", t.name, lo, hi)
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"runtime"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/samarthasthan/snowflake"
+)
+
+// benchResult is the machine-readable output of the bench command
+type benchResult struct {
+	Version      string  `json:"version"`
+	Goroutines   int     `json:"goroutines"`
+	Fair         bool    `json:"fair"`
+	IDs          int     `json:"ids"`
+	DurationNS   int64   `json:"duration_ns"`
+	IDsPerSecond float64 `json:"ids_per_second"`
+	P50NS        int64   `json:"p50_ns"`
+	P99NS        int64   `json:"p99_ns"`
+	MaxNS        int64   `json:"max_ns"`
+	AllocsPerOp  float64 `json:"allocs_per_op"`
+}
+
+// runBench measures NextID throughput and latency on this machine
+func runBench(args []string) error {
+	fs := flag.NewFlagSet("bench", flag.ContinueOnError)
+	n := fs.Int("n", 1_000_000, "total IDs to generate")
+	goroutines := fs.Int("goroutines", 1, "concurrent callers sharing one generator")
+	fair := fs.Bool("fair", false, "use FIFO fairness (Config.Fair)")
+	asJSON := fs.Bool("json", false, "print results as JSON")
+	v := snowflake.Version0
+	fs.Var(&v, "version", "layout version (number, vN or name)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *n < 1 || *goroutines < 1 {
+		return errors.New("--n and --goroutines must be positive")
+	}
+
+	gen, err := snowflake.NewGenerator(snowflake.Config{Version: v, Fair: *fair})
+	if err != nil {
+		return err
+	}
+
+	latencies := make([][]time.Duration, *goroutines)
+	errs := make([]error, *goroutines)
+
+	var before, after runtime.MemStats
+	runtime.GC()
+	runtime.ReadMemStats(&before)
+
+	var wg sync.WaitGroup
+	start := time.Now()
+	for w := range *goroutines {
+		count := *n / *goroutines
+		if w < *n%*goroutines {
+			count++
+		}
+		lat := make([]time.Duration, 0, count)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range count {
+				t := time.Now()
+				if _, err := gen.NextID(); err != nil {
+					errs[w] = err
+					break
+				}
+				lat = append(lat, time.Since(t))
+			}
+			latencies[w] = lat
+		}()
+	}
+	wg.Wait()
+	elapsed := time.Since(start)
+	runtime.ReadMemStats(&after)
+
+	if err := errors.Join(errs...); err != nil {
+		return err
+	}
+
+	var all []time.Duration
+	for _, lat := range latencies {
+		all = append(all, lat...)
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i] < all[j] })
+
+	// Latency slices are preallocated, so remaining allocations are the
+	// generator's own
+	result := benchResult{
+		Version:      v.String(),
+		Goroutines:   *goroutines,
+		Fair:         *fair,
+		IDs:          len(all),
+		DurationNS:   elapsed.Nanoseconds(),
+		IDsPerSecond: float64(len(all)) / elapsed.Seconds(),
+		P50NS:        percentile(all, 0.50).Nanoseconds(),
+		P99NS:        percentile(all, 0.99).Nanoseconds(),
+		MaxNS:        all[len(all)-1].Nanoseconds(),
+		AllocsPerOp:  float64(after.Mallocs-before.Mallocs) / float64(len(all)),
+	}
+
+	if *asJSON {
+		return json.NewEncoder(os.Stdout).Encode(result)
+	}
+	fmt.Printf("version:     %s\n", result.Version)
+	fmt.Printf("goroutines:  %d (fair: %t)\n", result.Goroutines, result.Fair)
+	fmt.Printf("ids:         %d in %s\n", result.IDs, elapsed.Round(time.Millisecond))
+	fmt.Printf("throughput:  %.0f IDs/s\n", result.IDsPerSecond)
+	fmt.Printf("latency:     p50 %s, p99 %s, max %s\n",
+		time.Duration(result.P50NS), time.Duration(result.P99NS), time.Duration(result.MaxNS))
+	fmt.Printf("allocs/op:   %.2f\n", result.AllocsPerOp)
+	return nil
+}
+
+// percentile returns the p-th quantile of sorted durations
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	return sorted[int(p*float64(len(sorted)-1))]
+}
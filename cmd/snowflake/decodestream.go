@@ -0,0 +1,47 @@
+package main
+
+import (
+	"encoding/binary"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/samarthasthan/snowflake"
+)
+
+// runDecodeStream decodes a binary stream of IDs, such as one written by
+// "snowflake gen --format u64le", into JSON lines
+func runDecodeStream(args []string) error {
+	fs := flag.NewFlagSet("decode-stream", flag.ContinueOnError)
+	in := fs.String("in", "-", "input file of 8-byte IDs (- for stdin)")
+	orderName := fs.String("order", "le", "byte order: le or be")
+	var layout snowflake.LayoutFlag
+	fs.Var(&layout, "version", "decode every ID with this version or preset layout, e.g. twitter (required for versionless IDs; default: each ID's own version)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	var order binary.ByteOrder
+	switch *orderName {
+	case "le":
+		order = binary.LittleEndian
+	case "be":
+		order = binary.BigEndian
+	default:
+		return fmt.Errorf("unknown byte order %q", *orderName)
+	}
+
+	var r io.Reader = os.Stdin
+	if *in != "-" {
+		f, err := os.Open(*in)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		r = f
+	}
+
+	_, err := snowflake.DecodeStream(os.Stdout, r, order, layout.Layout)
+	return err
+}
@@ -0,0 +1,295 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/samarthasthan/snowflake"
+	"gopkg.in/yaml.v3"
+)
+
+// severity grades a doctor finding
+type severity int
+
+const (
+	sevOK severity = iota
+	sevWarn
+	sevFail
+)
+
+func (s severity) String() string {
+	return [...]string{"ok", "warn", "fail"}[s]
+}
+
+// finding is one result of a doctor check, with a suggested fix for
+// anything that is not ok
+type finding struct {
+	Check    string   `json:"check"`
+	Severity severity `json:"-"`
+	Level    string   `json:"level"`
+	Message  string   `json:"message"`
+	Fix      string   `json:"fix,omitempty"`
+}
+
+// doctor collects findings
+type doctor struct {
+	findings []finding
+}
+
+func (d *doctor) report(check string, sev severity, fix, format string, args ...any) {
+	d.findings = append(d.findings, finding{
+		Check:    check,
+		Severity: sev,
+		Level:    sev.String(),
+		Message:  fmt.Sprintf(format, args...),
+		Fix:      fix,
+	})
+}
+
+// runDoctor checks a host and generator config before a service goes live
+func runDoctor(args []string) error {
+	fs := flag.NewFlagSet("doctor", flag.ContinueOnError)
+	configPath := fs.String("config", "", "generator config file in Config's YAML form")
+	statePath := fs.String("state", "", "FileStateStore path the service will use")
+	node := fs.Uint64("node", 0, "node ID (ignored with --config)")
+	rate := fs.Float64("rate", 0, "peak IDs/s the node must sustain (0 skips the comparison)")
+	duration := fs.Duration("duration", 200*time.Millisecond, "how long to measure throughput")
+	asJSON := fs.Bool("json", false, "print findings as JSON")
+	v := snowflake.Version0
+	fs.Var(&v, "version", "layout version (number, vN or name; ignored with --config)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	var d doctor
+	var layout *snowflake.VersionLayout
+	cfg, ok := snowflake.Config{Version: v, NodeID: *node}, true
+	if *configPath != "" {
+		cfg, ok = d.checkConfigFile(*configPath)
+	}
+	if ok {
+		layout = d.checkConfig(cfg)
+	}
+	d.checkClock(layout)
+	if *statePath != "" && layout != nil {
+		d.checkState(*statePath, cfg, layout)
+	}
+	if layout != nil {
+		d.checkThroughput(cfg, layout, *rate, *duration)
+	}
+
+	failed := 0
+	for _, f := range d.findings {
+		if f.Severity == sevFail {
+			failed++
+		}
+	}
+
+	if *asJSON {
+		if err := json.NewEncoder(os.Stdout).Encode(d.findings); err != nil {
+			return err
+		}
+	} else {
+		for _, f := range d.findings {
+			fmt.Printf("[%-4s] %-10s %s\n", f.Level, f.Check, f.Message)
+			if f.Fix != "" {
+				fmt.Printf("       %-10s fix: %s\n", "", f.Fix)
+			}
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d check(s) failed", failed)
+	}
+	return nil
+}
+
+// checkConfigFile parses a YAML config file, reporting false if it cannot
+func (d *doctor) checkConfigFile(path string) (snowflake.Config, bool) {
+	var cfg snowflake.Config
+	data, err := os.ReadFile(path)
+	if err == nil {
+		dec := yaml.NewDecoder(bytes.NewReader(data))
+		dec.KnownFields(true)
+		err = dec.Decode(&cfg)
+	}
+	if err != nil {
+		d.report("config", sevFail, "fix the file; keys are those of Config's YAML form (node_id, min_remaining, ...)",
+			"%s: %v", path, err)
+		return cfg, false
+	}
+	d.report("config", sevOK, "", "%s parsed", path)
+	return cfg, true
+}
+
+// checkConfig validates cfg against the layout and clock, returning the
+// layout it resolves to, or nil if there is none
+func (d *doctor) checkConfig(cfg snowflake.Config) *snowflake.VersionLayout {
+	layout, ok := snowflake.LookupLayout(cfg.Version)
+	switch {
+	case cfg.Layout != nil:
+		layout, ok = *cfg.Layout, true
+	case cfg.NodeBits != 0 || cfg.SequenceBits != 0 || cfg.TimeUnit != 0:
+		// A bit split is registered by the first generator using it
+		if _, err := snowflake.NewGenerator(snowflake.Config{
+			Version: cfg.Version, NodeBits: cfg.NodeBits, SequenceBits: cfg.SequenceBits, TimeUnit: cfg.TimeUnit,
+		}); err == nil {
+			layout, ok = snowflake.LookupLayout(cfg.Version)
+		}
+	}
+
+	if err := cfg.Validate(); err != nil {
+		fix := ""
+		switch {
+		case errors.Is(err, snowflake.ErrInvalidNodeID):
+			fix = fmt.Sprintf("use a node ID from 0 to %d, or a layout with more node bits", layout.MaxNodeID)
+		case errors.Is(err, snowflake.ErrEpochInFuture), errors.Is(err, snowflake.ErrClockBeforeFloor):
+			fix = "check the host clock; it may have booted without a time source"
+		case errors.Is(err, snowflake.ErrHorizonReached), errors.Is(err, snowflake.ErrTimestampOverflow):
+			fix = "migrate to a layout with more time bits or a later epoch"
+		}
+		d.report("config", sevFail, fix, "%v", err)
+	} else {
+		d.report("config", sevOK, "", "version %s, node %d", cfg.Version, cfg.NodeID)
+	}
+	if !ok {
+		return nil
+	}
+
+	// Durations saturate at 292 years, so count years by date
+	end, now := layout.ExhaustionTime(), time.Now()
+	years := float64(end.Year()-now.Year()) + float64(end.YearDay()-now.YearDay())/365
+	switch {
+	case years < 1:
+		d.report("lifetime", sevFail, "plan the version migration now",
+			"layout exhausted on %s", layout.ExhaustionTime().Format(time.DateOnly))
+	case years < 5:
+		d.report("lifetime", sevWarn, "plan a migration to a layout with more time bits",
+			"%.1f years left (until %s)", years, end.Format(time.DateOnly))
+	default:
+		d.report("lifetime", sevOK, "", "%.0f years left (until %s)", years, end.Format(time.DateOnly))
+	}
+	return &layout
+}
+
+// checkClock reports the kernel's clock sync status and whether the clock
+// resolves the layout's time unit
+func (d *doctor) checkClock(layout *snowflake.VersionLayout) {
+	synced, estErr, err := clockSyncStatus()
+	switch {
+	case errors.Is(err, errors.ErrUnsupported):
+		d.report("clock", sevWarn, "verify NTP or PTP sync by other means",
+			"sync status is not available on this platform")
+	case err != nil:
+		d.report("clock", sevWarn, "verify NTP or PTP sync by other means", "reading sync status: %v", err)
+	case !synced:
+		d.report("clock", sevFail, "enable and check chronyd, ntpd or systemd-timesyncd",
+			"kernel reports the clock as unsynchronized")
+	default:
+		d.report("clock", sevOK, "", "synchronized (estimated error %s)", estErr)
+	}
+
+	if layout == nil {
+		return
+	}
+	if res := clockResolution(); res > layout.TimeUnit {
+		d.report("clock", sevWarn, "expect bursts to exhaust the sequence and wait for the clock",
+			"clock ticks every %s, coarser than the %s time unit", res, layout.TimeUnit)
+	}
+}
+
+// clockResolution estimates the smallest step of time.Now
+func clockResolution() time.Duration {
+	best := time.Duration(1<<63 - 1)
+	for range 10 {
+		start := time.Now()
+		next := time.Now()
+		for next.Equal(start) {
+			next = time.Now()
+		}
+		best = min(best, next.Sub(start))
+	}
+	return best
+}
+
+// checkState reports whether a FileStateStore at path can serve cfg
+func (d *doctor) checkState(path string, cfg snowflake.Config, layout *snowflake.VersionLayout) {
+	const check = "state"
+	s, err := snowflake.FileStateStore{Path: path}.Load(context.Background())
+	switch {
+	case errors.Is(err, snowflake.ErrNoState):
+		probe, err := os.CreateTemp(filepath.Dir(path), ".doctor-*")
+		if err != nil {
+			d.report(check, sevFail, "create the directory on a persistent volume the service can write",
+				"%s cannot be created: %v", path, err)
+			return
+		}
+		probe.Close()
+		os.Remove(probe.Name())
+		d.report(check, sevOK, "", "%s will be created on first use", path)
+	case err != nil:
+		d.report(check, sevFail, "remove or repair the file", "%v", err)
+	case s.Version != layout.Version || s.NodeID != cfg.NodeID:
+		d.report(check, sevFail, "point the service at its own state file, or fix its node ID",
+			"%s belongs to version %d node %d", path, s.Version, s.NodeID)
+	case time.Until(s.Until) > time.Second:
+		d.report(check, sevWarn, "check whether the clock was set back since the last run",
+			"saved mark is %s ahead of the clock; the generator will wait for it", time.Until(s.Until).Round(time.Millisecond))
+	default:
+		d.report(check, sevOK, "", "%s is valid, last mark %s", path, s.Until.Format(time.RFC3339))
+	}
+}
+
+// checkThroughput measures NextID on this host and compares it with the
+// required rate
+func (d *doctor) checkThroughput(cfg snowflake.Config, layout *snowflake.VersionLayout, rate float64, duration time.Duration) {
+	const check = "throughput"
+	if rate > layout.MaxIDsPerSecond() {
+		d.report(check, sevFail, "use a layout with more sequence bits or a finer time unit",
+			"%.0f IDs/s exceeds the layout's %.0f per node", rate, layout.MaxIDsPerSecond())
+		return
+	}
+
+	// Measure without side effects on the service's state or audit trail
+	gen, err := snowflake.NewGenerator(snowflake.Config{
+		Version: cfg.Version, Layout: cfg.Layout, NodeID: cfg.NodeID,
+		NodeBits: cfg.NodeBits, SequenceBits: cfg.SequenceBits, TimeUnit: cfg.TimeUnit,
+		Fields: cfg.Fields, WaitStrategy: cfg.WaitStrategy,
+	})
+	if err != nil {
+		d.report(check, sevWarn, "", "not measured: %v", err)
+		return
+	}
+
+	n := 0
+	start := time.Now()
+	for time.Since(start) < duration {
+		for range 1024 {
+			if _, err := gen.NextID(); err != nil {
+				d.report(check, sevFail, "", "NextID failed: %v", err)
+				return
+			}
+		}
+		n += 1024
+	}
+	measured := float64(n) / time.Since(start).Seconds()
+
+	switch {
+	case rate == 0:
+		d.report(check, sevOK, "", "%.0f IDs/s on one goroutine", measured)
+	case measured < rate:
+		d.report(check, sevFail, "spread load over more nodes or use a layout with more sequence bits",
+			"%.0f IDs/s measured, %.0f required", measured, rate)
+	case measured < 2*rate:
+		d.report(check, sevWarn, "leave headroom for bursts and slower hosts",
+			"%.0f IDs/s measured, less than twice the %.0f required", measured, rate)
+	default:
+		d.report(check, sevOK, "", "%.0f IDs/s measured, %.0f required", measured, rate)
+	}
+}
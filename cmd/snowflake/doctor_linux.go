@@ -0,0 +1,23 @@
+//go:build linux
+
+package main
+
+import (
+	"syscall"
+	"time"
+)
+
+// timeError is the adjtimex state of a clock the kernel considers
+// unsynchronized
+const timeError = 5
+
+// clockSyncStatus reports whether the kernel considers the clock
+// synchronized by NTP or PTP, and its estimated error
+func clockSyncStatus() (bool, time.Duration, error) {
+	var tx syscall.Timex
+	state, err := syscall.Adjtimex(&tx)
+	if err != nil {
+		return false, 0, err
+	}
+	return state != timeError, time.Duration(tx.Esterror) * time.Microsecond, nil
+}
@@ -0,0 +1,13 @@
+//go:build !linux
+
+package main
+
+import (
+	"errors"
+	"time"
+)
+
+// clockSyncStatus is only implemented on Linux
+func clockSyncStatus() (bool, time.Duration, error) {
+	return false, 0, errors.ErrUnsupported
+}
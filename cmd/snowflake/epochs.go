@@ -0,0 +1,36 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/samarthasthan/snowflake"
+)
+
+// runEpochs lists the named epochs, or resolves a single epoch name
+func runEpochs(args []string) error {
+	fs := flag.NewFlagSet("epochs", flag.ContinueOnError)
+	resolve := fs.String("resolve", "", "resolve an epoch name, date or Unix milliseconds")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *resolve != "" {
+		epoch, err := snowflake.ParseEpoch(*resolve)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%s\t%d\n", epoch.Format(time.RFC3339Nano), epoch.UnixMilli())
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tEPOCH\tUNIX MS")
+	for _, e := range snowflake.NamedEpochs() {
+		fmt.Fprintf(w, "%s\t%s\t%d\n", e.Name, e.Epoch.Format(time.RFC3339Nano), e.Epoch.UnixMilli())
+	}
+	return w.Flush()
+}
@@ -0,0 +1,183 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strconv"
+	"time"
+
+	"github.com/samarthasthan/snowflake"
+)
+
+// genCheckpoint records the progress of a gen run next to its output file,
+// so an interrupted run can be resumed with the same flags
+type genCheckpoint struct {
+	Version string `json:"version"`
+	Node    uint64 `json:"node"`
+	Format  string `json:"format"`
+	Count   int64  `json:"count"`
+	Written int64  `json:"written"`
+	Offset  int64  `json:"offset"`
+	LastID  uint64 `json:"last_id"`
+}
+
+// genFormats maps output formats to record encoders
+var genFormats = map[string]func(buf []byte, id uint64) []byte{
+	"u64le": binary.LittleEndian.AppendUint64,
+	"u64be": binary.BigEndian.AppendUint64,
+	"text": func(buf []byte, id uint64) []byte {
+		return append(strconv.AppendUint(buf, id, 10), '\n')
+	},
+}
+
+// runGen writes count IDs to a file, checkpointing after every batch. An
+// interrupted run leaves a checkpoint that the next run with the same
+// flags resumes from.
+func runGen(args []string) error {
+	fs := flag.NewFlagSet("gen", flag.ContinueOnError)
+	count := fs.Int64("count", 0, "number of IDs to write")
+	out := fs.String("out", "", "output file")
+	format := fs.String("format", "u64le", "record format: u64le, u64be or text")
+	node := fs.Uint64("node", 0, "node ID")
+	batch := fs.Int("batch", 65536, "IDs generated and flushed per checkpoint")
+	v := snowflake.Version0
+	fs.Var(&v, "version", "layout version (number, vN or name)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *count < 1 || *batch < 1 {
+		return errors.New("--count and --batch must be positive")
+	}
+	if *out == "" {
+		return errors.New("--out is required")
+	}
+	encode, ok := genFormats[*format]
+	if !ok {
+		return fmt.Errorf("unknown format %q", *format)
+	}
+	layout, ok := snowflake.LookupLayout(v)
+	if !ok {
+		return fmt.Errorf("%w: %d", snowflake.ErrInvalidVersion, v)
+	}
+
+	want := genCheckpoint{Version: v.String(), Node: *node, Format: *format, Count: *count}
+	ckptPath := *out + ".ckpt"
+	ckpt, err := loadCheckpoint(ckptPath, want)
+	if err != nil {
+		return err
+	}
+
+	cfg := snowflake.Config{Version: v, NodeID: *node}
+	if ckpt.Written > 0 {
+		// IDs minted after resuming must sort after those already written
+		last, err := snowflake.DecodeWithLayout(ckpt.LastID, &layout)
+		if err != nil {
+			return err
+		}
+		cfg.NotBefore = last.Time.Add(layout.TimeUnit)
+		if wait := time.Until(cfg.NotBefore); wait > 0 && wait < time.Second {
+			time.Sleep(wait)
+		}
+	}
+	gen, err := snowflake.NewGenerator(cfg)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(*out, os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	// Drop anything written after the last checkpoint
+	if err := f.Truncate(ckpt.Offset); err != nil {
+		return err
+	}
+	if _, err := f.Seek(ckpt.Offset, io.SeekStart); err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	w := bufio.NewWriterSize(f, 1<<20)
+	var buf []byte
+	for ckpt.Written < ckpt.Count {
+		if ctx.Err() != nil {
+			return fmt.Errorf("interrupted after %d of %d IDs; rerun to resume", ckpt.Written, ckpt.Count)
+		}
+
+		n := min(int64(*batch), ckpt.Count-ckpt.Written)
+		ids, err := gen.NextIDs(int(n))
+		if err != nil {
+			return err
+		}
+		for _, id := range ids {
+			buf = encode(buf[:0], id)
+			if _, err := w.Write(buf); err != nil {
+				return err
+			}
+			ckpt.Offset += int64(len(buf))
+		}
+		if err := w.Flush(); err != nil {
+			return err
+		}
+		if err := f.Sync(); err != nil {
+			return err
+		}
+
+		ckpt.Written += n
+		ckpt.LastID = ids[len(ids)-1]
+		if err := saveCheckpoint(ckptPath, ckpt); err != nil {
+			return err
+		}
+	}
+
+	return os.Remove(ckptPath)
+}
+
+// loadCheckpoint reads the checkpoint at path, or returns a fresh one if
+// there is none. A checkpoint left by a run with different flags is an
+// error rather than being silently discarded.
+func loadCheckpoint(path string, want genCheckpoint) (genCheckpoint, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return want, nil
+	}
+	if err != nil {
+		return want, err
+	}
+
+	var ckpt genCheckpoint
+	if err := json.Unmarshal(data, &ckpt); err != nil {
+		return want, fmt.Errorf("%s: %w", path, err)
+	}
+	if ckpt.Version != want.Version || ckpt.Node != want.Node ||
+		ckpt.Format != want.Format || ckpt.Count != want.Count {
+		return want, fmt.Errorf("%s was written with different flags; remove it to start over", path)
+	}
+	return ckpt, nil
+}
+
+// saveCheckpoint replaces the checkpoint at path atomically
+func saveCheckpoint(path string, ckpt genCheckpoint) error {
+	data, err := json.Marshal(ckpt)
+	if err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
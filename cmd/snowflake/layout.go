@@ -0,0 +1,37 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"os"
+
+	"github.com/samarthasthan/snowflake"
+)
+
+// runLayout dispatches the layout subcommands
+func runLayout(args []string) error {
+	if len(args) == 0 || args[0] != "export" {
+		return errors.New("usage: snowflake layout export [--version N]")
+	}
+
+	fs := flag.NewFlagSet("layout export", flag.ContinueOnError)
+	version := fs.String("version", "", "export a single layout version (number, vN or name)")
+	if err := fs.Parse(args[1:]); err != nil {
+		return err
+	}
+
+	var out any = snowflake.Layouts()
+	if *version != "" {
+		v, err := snowflake.ParseVersion(*version)
+		if err != nil {
+			return err
+		}
+		layout, _ := snowflake.LookupLayout(v)
+		out = layout
+	}
+
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(out)
+}
@@ -0,0 +1,62 @@
+// Command snowflake is a toolbox for working with Snowflake IDs.
+package main
+
+import (
+	"fmt"
+	"os"
+)
+
+// command is a single snowflake subcommand
+type command struct {
+	name    string
+	summary string
+	run     func(args []string) error
+}
+
+var commands = []command{
+	{name: "range", summary: "Print ID boundaries for a time window", run: runRange},
+	{name: "layout", summary: "Export layout specs as JSON", run: runLayout},
+	{name: "epochs", summary: "List or resolve named epochs", run: runEpochs},
+	{name: "vectors", summary: "Emit reference test vectors for other-language ports", run: runVectors},
+	{name: "schema", summary: "Print the OpenAPI/JSON Schema definition for IDs", run: runSchema},
+	{name: "bench", summary: "Measure generator throughput and latency", run: runBench},
+	{name: "nodemap", summary: "Assign stable node IDs to an inventory of hosts", run: runNodemap},
+	{name: "gen", summary: "Write IDs to a file, resuming interrupted runs", run: runGen},
+	{name: "anonymize", summary: "Replace IDs with keyed one-way pseudonyms for exports", run: runAnonymize},
+	{name: "decode-stream", summary: "Decode a binary stream of IDs into JSON lines", run: runDecodeStream},
+	{name: "doctor", summary: "Check clock, config, state file and throughput before going live", run: runDoctor},
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+
+	name := os.Args[1]
+	for _, cmd := range commands {
+		if cmd.name != name {
+			continue
+		}
+		if err := cmd.run(os.Args[2:]); err != nil {
+			fmt.Fprintf(os.Stderr, "snowflake %s: %v\n", name, err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	if name != "help" && name != "-h" && name != "--help" {
+		fmt.Fprintf(os.Stderr, "snowflake: unknown command %q\n\n", name)
+	}
+	usage()
+	os.Exit(2)
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "Usage: snowflake <command> [flags]")
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintln(os.Stderr, "Commands:")
+	for _, cmd := range commands {
+		fmt.Fprintf(os.Stderr, "  %-14s %s\n", cmd.name, cmd.summary)
+	}
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/samarthasthan/snowflake"
+)
+
+// runNodemap assigns node IDs to an inventory of hosts and prints the
+// mapping for infrastructure-as-code tools
+func runNodemap(args []string) error {
+	fs := flag.NewFlagSet("nodemap", flag.ContinueOnError)
+	inventory := fs.String("inventory", "", "file listing one host or pod per line (- for stdin)")
+	previous := fs.String("previous", "", "JSON mapping from an earlier run whose assignments are kept")
+	format := fs.String("format", "json", "output format: json or hcl")
+	v := snowflake.Version0
+	fs.Var(&v, "version", "layout version (number, vN or name)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *inventory == "" {
+		return errors.New("--inventory is required")
+	}
+	if *format != "json" && *format != "hcl" {
+		return fmt.Errorf("unknown format %q", *format)
+	}
+
+	layout, ok := snowflake.LookupLayout(v)
+	if !ok {
+		return fmt.Errorf("%w: %d", snowflake.ErrInvalidVersion, v)
+	}
+
+	hosts, err := readInventory(*inventory)
+	if err != nil {
+		return err
+	}
+
+	var pinned map[string]uint64
+	if *previous != "" {
+		data, err := os.ReadFile(*previous)
+		if err != nil {
+			return err
+		}
+		if err := json.Unmarshal(data, &pinned); err != nil {
+			return fmt.Errorf("%s: %w", *previous, err)
+		}
+	}
+
+	mapping, err := snowflake.AssignNodeIDs(hosts, layout.MaxNodeID, pinned)
+	if err != nil {
+		return err
+	}
+
+	if *format == "hcl" {
+		return writeHCL(os.Stdout, mapping)
+	}
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(mapping)
+}
+
+// readInventory reads host names, skipping blank lines and # comments
+func readInventory(path string) ([]string, error) {
+	var r io.Reader = os.Stdin
+	if path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		r = f
+	}
+
+	var hosts []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		hosts = append(hosts, line)
+	}
+	return hosts, scanner.Err()
+}
+
+// writeHCL writes the mapping as an HCL map attribute
+func writeHCL(w io.Writer, mapping map[string]uint64) error {
+	hosts := make([]string, 0, len(mapping))
+	for host := range mapping {
+		hosts = append(hosts, host)
+	}
+	sort.Strings(hosts)
+
+	bw := bufio.NewWriter(w)
+	fmt.Fprintln(bw, "node_ids = {")
+	for _, host := range hosts {
+		fmt.Fprintf(bw, "  %q = %d\n", host, mapping[host])
+	}
+	fmt.Fprintln(bw, "}")
+	return bw.Flush()
+}
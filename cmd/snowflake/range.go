@@ -0,0 +1,114 @@
+package main
+
+import (
+	"encoding/csv"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/samarthasthan/snowflake"
+)
+
+// runRange prints the ID boundaries of a time window as CSV, optionally
+// split into fixed-size segments and annotated with a SQL predicate
+func runRange(args []string) error {
+	fs := flag.NewFlagSet("range", flag.ContinueOnError)
+	fromFlag := fs.String("from", "", "start of the window (YYYY-MM-DD or RFC 3339, inclusive)")
+	toFlag := fs.String("to", "", "end of the window (YYYY-MM-DD or RFC 3339, exclusive)")
+	node := fs.Int64("node", -1, "restrict to a single node ID (-1 for all nodes)")
+	v := snowflake.Version0
+	fs.Var(&v, "version", "layout version (number, vN or name)")
+	step := fs.Duration("step", 0, "split the window into segments of this size (0 for one segment)")
+	sql := fs.Bool("sql", false, "add a SQL predicate column")
+	column := fs.String("column", "id", "ID column name used in the SQL predicate")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *fromFlag == "" || *toFlag == "" {
+		return errors.New("--from and --to are required")
+	}
+	from, err := parseTime(*fromFlag)
+	if err != nil {
+		return err
+	}
+	to, err := parseTime(*toFlag)
+	if err != nil {
+		return err
+	}
+	if *step < 0 {
+		return errors.New("--step must not be negative")
+	}
+
+	layout, ok := snowflake.LookupLayout(v)
+	if !ok {
+		return fmt.Errorf("%w: %d", snowflake.ErrInvalidVersion, v)
+	}
+
+	w := csv.NewWriter(os.Stdout)
+	header := []string{"from", "to", "node", "min_id", "max_id"}
+	if *sql {
+		header = append(header, "sql")
+	}
+	if err := w.Write(header); err != nil {
+		return err
+	}
+
+	for start := from; start.Before(to); {
+		end := to
+		if *step > 0 && start.Add(*step).Before(to) {
+			end = start.Add(*step)
+		}
+
+		var lo, hi uint64
+		nodeLabel := "*"
+		if *node < 0 {
+			lo, hi, err = snowflake.IDBounds(v, start, end)
+		} else {
+			lo, hi, err = snowflake.NodeIDBounds(v, start, end, uint64(*node))
+			nodeLabel = strconv.FormatInt(*node, 10)
+		}
+		if err != nil {
+			return err
+		}
+
+		record := []string{
+			start.Format(time.RFC3339),
+			end.Format(time.RFC3339),
+			nodeLabel,
+			strconv.FormatUint(lo, 10),
+			strconv.FormatUint(hi, 10),
+		}
+		if *sql {
+			predicate := fmt.Sprintf("%s BETWEEN %d AND %d", *column, lo, hi)
+			if *node >= 0 {
+				predicate += fmt.Sprintf(" AND (%s >> %d) & %d = %d",
+					*column, layout.Shifts().Node, layout.MaxNodeID, *node)
+			}
+			record = append(record, predicate)
+		}
+		if err := w.Write(record); err != nil {
+			return err
+		}
+
+		start = end
+	}
+
+	w.Flush()
+	return w.Error()
+}
+
+// parseTime accepts a bare date (UTC midnight) or an RFC 3339 timestamp
+func parseTime(s string) (time.Time, error) {
+	if t, err := time.Parse(time.DateOnly, s); err == nil {
+		return t, nil
+	}
+	t, err := time.Parse(time.RFC3339Nano, s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid time %q: want YYYY-MM-DD or RFC 3339", s)
+	}
+	return t, nil
+}
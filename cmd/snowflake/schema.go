@@ -0,0 +1,38 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/samarthasthan/snowflake"
+)
+
+// runSchema prints the OpenAPI/JSON Schema definition for IDs
+func runSchema(args []string) error {
+	fs := flag.NewFlagSet("schema", flag.ContinueOnError)
+	format := fs.String("format", "string", "ID representation: string or int64")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	var f snowflake.SchemaFormat
+	switch *format {
+	case "string":
+		f = snowflake.SchemaString
+	case "int64":
+		f = snowflake.SchemaInt64
+	default:
+		return fmt.Errorf("unknown format %q", *format)
+	}
+
+	schema, err := snowflake.IDSchema(f)
+	if err != nil {
+		return err
+	}
+
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(schema)
+}
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"os"
+
+	"github.com/samarthasthan/snowflake"
+)
+
+// runVectors prints reference test vectors as JSON lines, one per vector
+func runVectors(args []string) error {
+	fs := flag.NewFlagSet("vectors", flag.ContinueOnError)
+	version := fs.String("version", "", "emit vectors for a single layout version (number, vN or name)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	var only *snowflake.Version
+	if *version != "" {
+		v, err := snowflake.ParseVersion(*version)
+		if err != nil {
+			return err
+		}
+		only = &v
+	}
+
+	enc := json.NewEncoder(os.Stdout)
+	for _, v := range snowflake.ReferenceVectors() {
+		if only != nil && (v.Layout != "" || v.Version != *only) {
+			continue
+		}
+		if err := enc.Encode(v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
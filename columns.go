@@ -0,0 +1,95 @@
+package snowflake
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// columnTimeUnits are the units of Columns.Time, coarsest first. They are
+// the units of Arrow timestamps.
+var columnTimeUnits = []time.Duration{time.Second, time.Millisecond, time.Microsecond, time.Nanosecond}
+
+// Columns holds decoded IDs column by column, for handing off to columnar
+// tooling. Each slice is a plain fixed-width array, so it can back an
+// Apache Arrow array without copying. This package does not depend on
+// Arrow; with arrow-go, for a layout in milliseconds:
+//
+//	buf := memory.NewBufferBytes(arrow.Int64Traits.CastToBytes(cols.Time))
+//	data := array.NewData(&arrow.TimestampType{Unit: arrow.Millisecond}, len(cols.Time), []*memory.Buffer{nil, buf}, nil, 0, 0)
+//	times := array.NewTimestampData(data)
+type Columns struct {
+	// Time holds each ID's time since the Unix epoch in TimeUnit, the
+	// coarsest of seconds, milliseconds, microseconds and nanoseconds that
+	// resolves the layout's time unit and epoch
+	Time     []int64
+	TimeUnit time.Duration
+
+	Node     []uint64
+	Sequence []uint64
+
+	// Fields holds a column per custom field of the layout
+	Fields map[string][]uint64
+}
+
+// DecodeColumns decodes ids with layout into columns. For versioned
+// layouts every ID must carry the layout's version; the error names the
+// first one that does not. IDs whose time does not fit an int64 in
+// TimeUnit fail with ErrTimestampOverflow.
+func DecodeColumns(ids []uint64, layout *VersionLayout) (*Columns, error) {
+	if err := layout.validate(); err != nil {
+		return nil, err
+	}
+
+	unit := columnTimeUnit(layout)
+	perSecond := int64(time.Second / unit)
+
+	cols := &Columns{
+		Time:     make([]int64, len(ids)),
+		TimeUnit: unit,
+		Node:     make([]uint64, len(ids)),
+		Sequence: make([]uint64, len(ids)),
+	}
+	if len(layout.Fields) > 0 {
+		cols.Fields = make(map[string][]uint64, len(layout.Fields))
+		for _, f := range layout.Fields {
+			cols.Fields[f.Name] = make([]uint64, len(ids))
+		}
+	}
+
+	timeShift := layout.timeShift()
+	nodeShift := layout.nodeShift()
+	seqShift := layout.sequenceShift()
+
+	for i, id := range ids {
+		if layout.VersionBits > 0 && !layout.matches(id) {
+			return nil, fmt.Errorf("%w: ID %d at index %d does not carry version %d", ErrInvalidVersion, id, i, layout.Version)
+		}
+
+		t := layout.timeOf((id >> timeShift) & layout.MaxTimestamp)
+		sec := t.Unix()
+		if sec > math.MaxInt64/perSecond-1 || sec < math.MinInt64/perSecond+1 {
+			return nil, fmt.Errorf("%w: ID %d at index %d is at %s, past int64 %s", ErrTimestampOverflow, id, i, t.Format(time.RFC3339), unit)
+		}
+		cols.Time[i] = sec*perSecond + int64(t.Nanosecond())/int64(unit)
+		cols.Node[i] = (id >> nodeShift) & layout.MaxNodeID
+		cols.Sequence[i] = (id >> seqShift) & layout.MaxSequence
+		for _, f := range layout.Fields {
+			shift, bits, _ := layout.field(f.Name)
+			cols.Fields[f.Name][i] = (id >> shift) & mask(bits)
+		}
+	}
+
+	return cols, nil
+}
+
+// columnTimeUnit returns the coarsest of columnTimeUnits dividing both the
+// layout's time unit and its epoch, so no ID's time is rounded
+func columnTimeUnit(layout *VersionLayout) time.Duration {
+	for _, unit := range columnTimeUnits {
+		if layout.TimeUnit%unit == 0 && layout.Epoch.Nanosecond()%int(unit) == 0 {
+			return unit
+		}
+	}
+	return time.Nanosecond
+}
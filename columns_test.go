@@ -0,0 +1,91 @@
+package snowflake
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestDecodeColumns(t *testing.T) {
+	layout, err := NewLayoutBuilder().
+		Time(41, time.Millisecond, EpochY2020).
+		Field("region", 4).
+		Field("node", 8).
+		Sequence(10).
+		Build()
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+
+	gen, err := NewGenerator(Config{Layout: layout, NodeID: 42, Fields: map[string]uint64{"region": 11}})
+	if err != nil {
+		t.Fatalf("Failed to create generator: %v", err)
+	}
+	ids, err := gen.NextIDs(100)
+	if err != nil {
+		t.Fatalf("Failed to generate IDs: %v", err)
+	}
+
+	cols, err := DecodeColumns(ids, layout)
+	if err != nil {
+		t.Fatalf("DecodeColumns failed: %v", err)
+	}
+	if len(cols.Time) != len(ids) || len(cols.Node) != len(ids) || len(cols.Sequence) != len(ids) || len(cols.Fields["region"]) != len(ids) {
+		t.Fatalf("Unexpected column lengths")
+	}
+
+	for i, id := range ids {
+		decoded, _ := DecodeWithLayout(id, layout)
+		if cols.Time[i] != decoded.Time.UnixMilli() || cols.Node[i] != decoded.NodeID ||
+			cols.Sequence[i] != decoded.Sequence || cols.Fields["region"][i] != 11 {
+			t.Fatalf("Row %d does not match %s", i, decoded)
+		}
+	}
+	if cols.TimeUnit != time.Millisecond {
+		t.Errorf("Expected milliseconds, got %s", cols.TimeUnit)
+	}
+}
+
+func TestDecodeColumns_TimeUnit(t *testing.T) {
+	tests := []struct {
+		layout *VersionLayout
+		unit   time.Duration
+	}{
+		{versionLayouts[Version3], time.Second},
+		{SonyflakeLayout(), time.Millisecond},
+		{TwitterLayout(), time.Millisecond},
+		{versionLayouts[Version2], time.Microsecond},
+	}
+	for _, tt := range tests {
+		id := tt.layout.encode(tt.layout.MaxTimestamp, 0, 0)
+		cols, err := DecodeColumns([]uint64{id}, tt.layout)
+		if err != nil {
+			t.Fatalf("DecodeColumns failed: %v", err)
+		}
+		decoded, _ := DecodeWithLayout(id, tt.layout)
+		perSecond := int64(time.Second / tt.unit)
+		got := time.Unix(cols.Time[0]/perSecond, cols.Time[0]%perSecond*int64(tt.unit))
+		if cols.TimeUnit != tt.unit || !got.Equal(decoded.Time) {
+			t.Errorf("Expected %s in %s, got %s in %s", decoded.Time, tt.unit, got, cols.TimeUnit)
+		}
+	}
+
+	// Nanoseconds wrap an int64 in 2262
+	layout, err := NewLayoutBuilder().Time(63, time.Nanosecond, EpochY2026).Sequence(1).Build()
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+	if _, err := DecodeColumns([]uint64{layout.encode(layout.MaxTimestamp, 0, 0)}, layout); !errors.Is(err, ErrTimestampOverflow) {
+		t.Errorf("Expected ErrTimestampOverflow, got %v", err)
+	}
+}
+
+func TestDecodeColumns_VersionMismatch(t *testing.T) {
+	layout := versionLayouts[Version0]
+	ids := []uint64{334076313601793, 7 << 61}
+
+	_, err := DecodeColumns(ids, layout)
+	if !errors.Is(err, ErrInvalidVersion) {
+		t.Fatalf("Expected ErrInvalidVersion, got %v", err)
+	}
+}
@@ -0,0 +1,23 @@
+package snowflake
+
+import "context"
+
+type callerTagKey struct{}
+
+// WithCallerTag returns a context identifying the caller by tag, for use
+// with audit logging and per-caller quotas
+func WithCallerTag(ctx context.Context, tag string) context.Context {
+	return context.WithValue(ctx, callerTagKey{}, tag)
+}
+
+// CallerTag returns the caller tag carried by ctx, or "" if none
+func CallerTag(ctx context.Context) string {
+	tag, _ := ctx.Value(callerTagKey{}).(string)
+	return tag
+}
+
+// NextIDContext generates the next unique ID on behalf of the caller tagged
+// in ctx
+func (g *Generator) NextIDContext(ctx context.Context) (uint64, error) {
+	return g.NextIDTagged(CallerTag(ctx))
+}
@@ -0,0 +1,42 @@
+package snowflake
+
+import "fmt"
+
+// DatacenterField is the name of the optional custom field holding a
+// datacenter ID. In layouts built with DatacenterLayout it sits directly
+// above the node field, which then holds the worker ID within the
+// datacenter, as in classic Twitter snowflake.
+const DatacenterField = "datacenter"
+
+// DatacenterLayout returns a copy of base with the top datacenterBits of
+// its node field split off into a datacenter field. IDs keep their bit
+// positions, so a split of TwitterLayout with 5 datacenter bits decodes
+// classic Twitter IDs as [41 bits time][5 bits datacenter][5 bits worker][12 bits
+// sequence]. Register the result, or pass it as Config.Layout.
+func DatacenterLayout(base *VersionLayout, datacenterBits uint8) (*VersionLayout, error) {
+	if base.NodeLast {
+		return nil, fmt.Errorf("%w: version %d node field is not next to custom fields", ErrInvalidLayout, base.Version)
+	}
+	if datacenterBits == 0 || datacenterBits >= base.NodeBits {
+		return nil, fmt.Errorf("%w: cannot split %d datacenter bits from a %d-bit node field",
+			ErrInvalidLayout, datacenterBits, base.NodeBits)
+	}
+
+	// The split is a different layout, so it does not inherit base's name
+	layout := base.clone()
+	layout.Name = ""
+	layout.Fields = append(layout.Fields, Field{Name: DatacenterField, Bits: datacenterBits})
+	layout.NodeBits -= datacenterBits
+	layout.MaxNodeID = mask(layout.NodeBits)
+
+	if err := layout.validate(); err != nil {
+		return nil, err
+	}
+	return layout, nil
+}
+
+// Datacenter returns the ID's datacenter field. It reports false if the
+// layout has no datacenter field. The worker ID is NodeID.
+func (d *DecodedID) Datacenter() (uint64, bool) {
+	return d.Field(DatacenterField)
+}
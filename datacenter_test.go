@@ -0,0 +1,73 @@
+package snowflake
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestDatacenterLayout_Twitter(t *testing.T) {
+	layout, err := DatacenterLayout(TwitterLayout(), 5)
+	if err != nil {
+		t.Fatalf("DatacenterLayout failed: %v", err)
+	}
+	if layout.NodeBits != 5 || layout.MaxNodeID != 31 {
+		t.Errorf("Expected a 5-bit worker field, got %+v", layout)
+	}
+
+	// Worker 347 of the unsplit layout is datacenter 10, worker 27
+	decoded, err := DecodeWithLayout(1050118621198921728, layout)
+	if err != nil {
+		t.Fatalf("DecodeWithLayout failed: %v", err)
+	}
+	if dc, ok := decoded.Datacenter(); !ok || dc != 10 || decoded.NodeID != 27 {
+		t.Errorf("Expected datacenter 10 worker 27, got %d (%v) %s", dc, ok, decoded)
+	}
+}
+
+func TestNewGenerator_DatacenterWorker(t *testing.T) {
+	twitter := TwitterLayout()
+	layout, _ := DatacenterLayout(twitter, 5)
+
+	gen, err := NewGenerator(Config{Layout: layout, DatacenterID: 3, WorkerID: 17})
+	if err != nil {
+		t.Fatalf("Failed to create generator: %v", err)
+	}
+	id, _ := gen.NextID()
+
+	decoded, _ := DecodeWithLayout(id, layout)
+	if dc, _ := decoded.Datacenter(); dc != 3 || decoded.NodeID != 17 {
+		t.Errorf("Expected datacenter 3 worker 17, got %s", decoded)
+	}
+
+	// The split keeps bit positions: the unsplit node ID is dc<<5 | worker
+	if unsplit, _ := DecodeWithLayout(id, twitter); unsplit.NodeID != 3<<5|17 {
+		t.Errorf("Expected unsplit node %d, got %d", 3<<5|17, unsplit.NodeID)
+	}
+}
+
+func TestNewGenerator_DatacenterErrors(t *testing.T) {
+	twitter := TwitterLayout()
+	layout, _ := DatacenterLayout(twitter, 5)
+
+	if _, err := NewGenerator(Config{Layout: layout, NodeID: 1, WorkerID: 2}); !errors.Is(err, ErrInvalidNodeID) {
+		t.Errorf("Expected ErrInvalidNodeID for conflicting NodeID and WorkerID, got %v", err)
+	}
+	if _, err := NewGenerator(Config{Layout: layout, WorkerID: 32}); !errors.Is(err, ErrInvalidNodeID) {
+		t.Errorf("Expected ErrInvalidNodeID for a worker beyond 5 bits, got %v", err)
+	}
+	if _, err := NewGenerator(Config{Layout: layout, DatacenterID: 32}); !errors.Is(err, ErrInvalidLayout) {
+		t.Errorf("Expected ErrInvalidLayout for a datacenter beyond 5 bits, got %v", err)
+	}
+	if _, err := NewGenerator(Config{Layout: layout, DatacenterID: 1, Fields: map[string]uint64{DatacenterField: 1}}); !errors.Is(err, ErrInvalidLayout) {
+		t.Errorf("Expected ErrInvalidLayout for DatacenterID and Fields both set, got %v", err)
+	}
+	if _, err := NewGenerator(Config{Version: Version0, DatacenterID: 1}); !errors.Is(err, ErrInvalidLayout) {
+		t.Errorf("Expected ErrInvalidLayout for a layout without a datacenter field, got %v", err)
+	}
+
+	for _, bits := range []uint8{0, 10} {
+		if _, err := DatacenterLayout(twitter, bits); !errors.Is(err, ErrInvalidLayout) {
+			t.Errorf("DatacenterLayout(%d): expected ErrInvalidLayout, got %v", bits, err)
+		}
+	}
+}
@@ -0,0 +1,24 @@
+package snowflake
+
+import "time"
+
+// deterministicStart is the time of the first clock read for seed 0
+var deterministicStart = time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)
+
+// DeterministicConfig returns a Config for reproducible tests, examples and
+// golden files: a generator built from it issues the same IDs on every run
+// and machine. It uses Version0 with node seed mod 256 and a StepClock
+// that starts seed seconds after 2026-02-01T00:00:00Z and advances 250µs
+// per read, so up to four consecutive IDs share a millisecond. Only clock
+// reads advance time; creating the generator and Warmup each take one.
+//
+// Other Config fields may be set on the result, though Quotas refill on
+// the real clock and so make output depend on timing again.
+func DeterministicConfig(seed uint64) Config {
+	layout, _ := lookupLayout(Version0)
+	return Config{
+		Version: Version0,
+		NodeID:  seed % (layout.MaxNodeID + 1),
+		Clock:   NewStepClock(deterministicStart.Add(time.Duration(seed%(1<<32))*time.Second), 250*time.Microsecond),
+	}
+}
@@ -0,0 +1,37 @@
+package snowflake
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestDeterministicConfig(t *testing.T) {
+	generate := func(seed uint64) []uint64 {
+		gen, err := NewGenerator(DeterministicConfig(seed))
+		if err != nil {
+			t.Fatalf("Failed to create generator: %v", err)
+		}
+		ids, err := gen.NextIDs(10)
+		if err != nil {
+			t.Fatalf("Failed to generate IDs: %v", err)
+		}
+		return ids
+	}
+
+	first := generate(42)
+	if !slices.Equal(first, generate(42)) {
+		t.Fatalf("Expected identical IDs for the same seed")
+	}
+	if slices.Equal(first, generate(43)) {
+		t.Errorf("Expected different IDs for different seeds")
+	}
+
+	decoded, err := Decode(first[0])
+	if err != nil {
+		t.Fatalf("Decode failed: %v", err)
+	}
+	want := deterministicStart.Add(42e9)
+	if decoded.NodeID != 42 || !decoded.Time.Equal(want) || decoded.Sequence != 0 {
+		t.Errorf("Unexpected first ID: %s", decoded)
+	}
+}
@@ -0,0 +1,66 @@
+package snowflake
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrComponentRange is returned when an ID component does not fit its field
+var ErrComponentRange = errors.New("component out of range for layout")
+
+// Shifts are the bit offsets of a layout's fields, counted from the least
+// significant bit. Version is zero for versionless layouts.
+type Shifts struct {
+	Version  uint8
+	Time     uint8
+	Node     uint8
+	Sequence uint8
+
+	// Fields holds the offsets of custom fields by name
+	Fields map[string]uint8
+}
+
+// Shifts returns the bit offsets of the layout's fields
+func (l *VersionLayout) Shifts() Shifts {
+	s := Shifts{
+		Time:     l.timeShift(),
+		Node:     l.nodeShift(),
+		Sequence: l.sequenceShift(),
+	}
+	if l.VersionBits > 0 {
+		s.Version = l.versionShift()
+	}
+	if len(l.Fields) > 0 {
+		s.Fields = make(map[string]uint8, len(l.Fields))
+		for _, f := range l.Fields {
+			shift, _, _ := l.field(f.Name)
+			s.Fields[f.Name] = shift
+		}
+	}
+	return s
+}
+
+// EncodeComponents packs raw components into an ID for layout, with any
+// custom fields zero (OR them in using Shifts). timestamp is in the
+// layout's time units since its epoch.
+//
+// This is a low-level primitive for pipelines such as replays and
+// migrations. It performs no coordination whatsoever: the caller alone is
+// responsible for never encoding the same (timestamp, node, sequence)
+// twice, and for not colliding with IDs a live Generator with the same
+// node ID is issuing. Prefer Generator for minting new IDs.
+func EncodeComponents(layout *VersionLayout, timestamp, nodeID, sequence uint64) (uint64, error) {
+	if err := layout.validate(); err != nil {
+		return 0, err
+	}
+	if timestamp > layout.MaxTimestamp {
+		return 0, fmt.Errorf("%w: timestamp %d (max: %d)", ErrComponentRange, timestamp, layout.MaxTimestamp)
+	}
+	if nodeID > layout.MaxNodeID {
+		return 0, fmt.Errorf("%w: %d (max: %d)", ErrInvalidNodeID, nodeID, layout.MaxNodeID)
+	}
+	if sequence > layout.MaxSequence {
+		return 0, fmt.Errorf("%w: sequence %d (max: %d)", ErrComponentRange, sequence, layout.MaxSequence)
+	}
+	return layout.encode(timestamp, nodeID, sequence), nil
+}
@@ -0,0 +1,56 @@
+package snowflake
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestEncodeComponents(t *testing.T) {
+	layout, _ := LookupLayout(Version0)
+
+	id, err := EncodeComponents(&layout, 12345, 7, 42)
+	if err != nil {
+		t.Fatalf("Failed to encode: %v", err)
+	}
+	decoded, _ := Decode(id)
+	if decoded.Timestamp != 12345 || decoded.NodeID != 7 || decoded.Sequence != 42 {
+		t.Errorf("Unexpected round trip: %s", decoded)
+	}
+
+	shifts := layout.Shifts()
+	manual := uint64(12345)<<shifts.Time | 7<<shifts.Node | 42<<shifts.Sequence
+	if id != manual {
+		t.Errorf("Expected shifts to reproduce %d, got %d", id, manual)
+	}
+
+	if _, err := EncodeComponents(&layout, layout.MaxTimestamp+1, 0, 0); !errors.Is(err, ErrComponentRange) {
+		t.Errorf("Expected ErrComponentRange for timestamp, got %v", err)
+	}
+	if _, err := EncodeComponents(&layout, 0, layout.MaxNodeID+1, 0); !errors.Is(err, ErrInvalidNodeID) {
+		t.Errorf("Expected ErrInvalidNodeID, got %v", err)
+	}
+	if _, err := EncodeComponents(&layout, 0, 0, layout.MaxSequence+1); !errors.Is(err, ErrComponentRange) {
+		t.Errorf("Expected ErrComponentRange for sequence, got %v", err)
+	}
+}
+
+func TestVersionLayout_Shifts(t *testing.T) {
+	layout, err := NewLayoutBuilder().
+		Version(6, 3).
+		Time(41, time.Millisecond, EpochY2026).
+		Field("region", 4).
+		Field("node", 6).
+		Sequence(10).
+		Build()
+	if err != nil {
+		t.Fatalf("Failed to build layout: %v", err)
+	}
+
+	want := Shifts{Version: 61, Time: 20, Node: 10, Sequence: 0, Fields: map[string]uint8{"region": 16}}
+	got := layout.Shifts()
+	if got.Version != want.Version || got.Time != want.Time || got.Node != want.Node ||
+		got.Sequence != want.Sequence || got.Fields["region"] != want.Fields["region"] {
+		t.Errorf("Expected %+v, got %+v", want, got)
+	}
+}
@@ -0,0 +1,76 @@
+package snowflake
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var ErrUnknownEpoch = errors.New("unknown epoch")
+
+// Well-known epochs used by Snowflake-style ID schemes
+var (
+	EpochUnix          = time.Unix(0, 0).UTC()
+	EpochTwitter2010   = time.UnixMilli(1288834974657).UTC() // 2010-11-04T01:42:54.657Z
+	EpochInstagram2011 = time.UnixMilli(1314220021721).UTC() // 2011-08-24T21:07:01.721Z
+	EpochSonyflake2014 = time.Date(2014, 9, 1, 0, 0, 0, 0, time.UTC)
+	EpochDiscord2015   = time.Date(2015, 1, 1, 0, 0, 0, 0, time.UTC)
+	EpochY2020         = time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	EpochY2026         = time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+)
+
+// NamedEpoch associates a well-known epoch with its name
+type NamedEpoch struct {
+	Name  string
+	Epoch time.Time
+}
+
+// namedEpochs lists the well-known epochs in the order they are documented
+var namedEpochs = []NamedEpoch{
+	{Name: "unix", Epoch: EpochUnix},
+	{Name: "twitter2010", Epoch: EpochTwitter2010},
+	{Name: "instagram2011", Epoch: EpochInstagram2011},
+	{Name: "sonyflake2014", Epoch: EpochSonyflake2014},
+	{Name: "discord2015", Epoch: EpochDiscord2015},
+	{Name: "y2020", Epoch: EpochY2020},
+	{Name: "y2026", Epoch: EpochY2026},
+}
+
+// epochAliases maps short names to their canonical epoch names
+var epochAliases = map[string]string{
+	"twitter": "twitter2010",
+	"discord": "discord2015",
+}
+
+// NamedEpochs returns the well-known epochs
+func NamedEpochs() []NamedEpoch {
+	return append([]NamedEpoch(nil), namedEpochs...)
+}
+
+// ParseEpoch resolves an epoch given by name (case-insensitive, e.g.
+// "twitter2010"), as a date or RFC 3339 timestamp, or as Unix milliseconds
+func ParseEpoch(s string) (time.Time, error) {
+	name := strings.ToLower(strings.TrimSpace(s))
+	if alias, ok := epochAliases[name]; ok {
+		name = alias
+	}
+	for _, e := range namedEpochs {
+		if e.Name == name {
+			return e.Epoch, nil
+		}
+	}
+
+	if t, err := time.Parse(time.DateOnly, s); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse(time.RFC3339Nano, s); err == nil {
+		return t, nil
+	}
+	if ms, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return time.UnixMilli(ms).UTC(), nil
+	}
+
+	return time.Time{}, fmt.Errorf("%w: %q", ErrUnknownEpoch, s)
+}
@@ -0,0 +1,46 @@
+package snowflake
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParseEpoch(t *testing.T) {
+	tests := []struct {
+		input string
+		want  time.Time
+	}{
+		{input: "unix", want: EpochUnix},
+		{input: "Twitter2010", want: EpochTwitter2010},
+		{input: "twitter", want: EpochTwitter2010},
+		{input: "discord", want: EpochDiscord2015},
+		{input: "y2026", want: EpochY2026},
+		{input: "2024-06-01", want: time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)},
+		{input: "2024-06-01T12:00:00Z", want: time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)},
+		{input: "1288834974657", want: EpochTwitter2010},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			got, err := ParseEpoch(tt.input)
+			if err != nil {
+				t.Fatalf("ParseEpoch(%q) failed: %v", tt.input, err)
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("ParseEpoch(%q) = %s, want %s", tt.input, got, tt.want)
+			}
+		})
+	}
+
+	if _, err := ParseEpoch("mastodon-ish"); !errors.Is(err, ErrUnknownEpoch) {
+		t.Errorf("Expected ErrUnknownEpoch, got %v", err)
+	}
+}
+
+func TestVersion0UsesY2026Epoch(t *testing.T) {
+	layout, _ := LookupLayout(Version0)
+	if !layout.Epoch.Equal(EpochY2026) {
+		t.Errorf("Expected Version0 epoch %s, got %s", EpochY2026, layout.Epoch)
+	}
+}
@@ -0,0 +1,38 @@
+package snowflake
+
+import "sync"
+
+// ticketLock is a FIFO mutex: goroutines acquire it in the order they
+// called Lock. Every Unlock wakes all waiters so the next ticket holder can
+// proceed, which costs throughput under heavy contention.
+type ticketLock struct {
+	mu      sync.Mutex
+	cond    sync.Cond
+	next    uint64
+	serving uint64
+}
+
+func newTicketLock() *ticketLock {
+	l := &ticketLock{}
+	l.cond.L = &l.mu
+	return l
+}
+
+// Lock takes a ticket and blocks until it is served
+func (l *ticketLock) Lock() {
+	l.mu.Lock()
+	ticket := l.next
+	l.next++
+	for ticket != l.serving {
+		l.cond.Wait()
+	}
+	l.mu.Unlock()
+}
+
+// Unlock serves the next ticket
+func (l *ticketLock) Unlock() {
+	l.mu.Lock()
+	l.serving++
+	l.mu.Unlock()
+	l.cond.Broadcast()
+}
@@ -0,0 +1,107 @@
+package snowflake
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestTicketLock_FIFO(t *testing.T) {
+	l := newTicketLock()
+	l.Lock()
+
+	const numWaiters = 5
+	var order []int
+	var orderMu sync.Mutex
+	var wg sync.WaitGroup
+
+	for i := 0; i < numWaiters; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			l.Lock()
+			orderMu.Lock()
+			order = append(order, i)
+			orderMu.Unlock()
+			l.Unlock()
+		}(i)
+
+		// Wait for the goroutine to take its ticket before starting the next
+		for {
+			l.mu.Lock()
+			queued := l.next == uint64(i)+2
+			l.mu.Unlock()
+			if queued {
+				break
+			}
+			time.Sleep(time.Millisecond)
+		}
+	}
+
+	l.Unlock()
+	wg.Wait()
+
+	for i, got := range order {
+		if got != i {
+			t.Fatalf("Expected FIFO order, got %v", order)
+		}
+	}
+}
+
+func TestNextID_ConcurrentFair(t *testing.T) {
+	gen, err := NewGenerator(Config{Version: Version0, NodeID: 1, Fair: true})
+	if err != nil {
+		t.Fatalf("Failed to create generator: %v", err)
+	}
+
+	const numGoroutines = 10
+	const idsPerGoroutine = 500
+
+	var wg sync.WaitGroup
+	idsChan := make(chan uint64, numGoroutines*idsPerGoroutine)
+
+	for i := 0; i < numGoroutines; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < idsPerGoroutine; j++ {
+				id, err := gen.NextID()
+				if err != nil {
+					t.Errorf("Failed to generate ID: %v", err)
+					return
+				}
+				idsChan <- id
+			}
+		}()
+	}
+
+	wg.Wait()
+	close(idsChan)
+
+	ids := make(map[uint64]bool, numGoroutines*idsPerGoroutine)
+	for id := range idsChan {
+		if ids[id] {
+			t.Fatalf("Duplicate ID generated in fair mode: %d", id)
+		}
+		ids[id] = true
+	}
+}
+
+// BenchmarkNextID_ParallelFair documents the throughput cost of FIFO
+// fairness; compare with BenchmarkNextID_Parallel.
+func BenchmarkNextID_ParallelFair(b *testing.B) {
+	gen, err := NewGenerator(Config{Version: Version0, NodeID: 1, Fair: true})
+	if err != nil {
+		b.Fatalf("Failed to create generator: %v", err)
+	}
+
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			_, err := gen.NextID()
+			if err != nil {
+				b.Fatalf("Failed to generate ID: %v", err)
+			}
+		}
+	})
+}
@@ -0,0 +1,21 @@
+package snowflake
+
+// FlagsField is the name of the optional custom field holding
+// application-defined flags, such as an entity type or environment, so IDs
+// can be classified without a lookup table. Add it to a layout with
+// LayoutBuilder.Field(FlagsField, bits) and set it per call with
+// NextIDWithFlags; Config.Fields sets the value NextID uses.
+const FlagsField = "flags"
+
+// NextIDWithFlags generates the next ID with its flags field set to flags.
+// The flags sit above the node and sequence, so IDs issued within the same
+// time unit with different flags are not ordered by issue time.
+func (g *Generator) NextIDWithFlags(flags uint64) (uint64, error) {
+	return g.nextIDWithField(FlagsField, flags)
+}
+
+// Flags returns the ID's flags field. It reports false if the layout has
+// no flags field.
+func (d *DecodedID) Flags() (uint64, bool) {
+	return d.Field(FlagsField)
+}
@@ -0,0 +1,60 @@
+package snowflake
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestNextIDWithFlags(t *testing.T) {
+	layout, err := NewLayoutBuilder().
+		Time(41, time.Millisecond, EpochY2020).
+		Field(FlagsField, 4).
+		Field("node", 8).
+		Sequence(10).
+		Build()
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+
+	gen, err := NewGenerator(Config{Layout: layout, NodeID: 9, Fields: map[string]uint64{FlagsField: 1}})
+	if err != nil {
+		t.Fatalf("Failed to create generator: %v", err)
+	}
+
+	id, err := gen.NextIDWithFlags(12)
+	if err != nil {
+		t.Fatalf("NextIDWithFlags failed: %v", err)
+	}
+	decoded, _ := DecodeWithLayout(id, layout)
+	if flags, ok := decoded.Flags(); !ok || flags != 12 || decoded.NodeID != 9 {
+		t.Errorf("Expected flags 12 on node 9, got %d (%v) %s", flags, ok, decoded)
+	}
+
+	// NextID keeps using the configured default
+	id, _ = gen.NextID()
+	decoded, _ = DecodeWithLayout(id, layout)
+	if flags, _ := decoded.Flags(); flags != 1 {
+		t.Errorf("Expected default flags 1, got %d", flags)
+	}
+
+	if _, err := gen.NextIDWithFlags(16); !errors.Is(err, ErrComponentRange) {
+		t.Errorf("Expected ErrComponentRange for flags beyond 4 bits, got %v", err)
+	}
+	if stats := gen.Stats(); stats.Issued != 2 {
+		t.Errorf("Expected 2 issued IDs, got %d", stats.Issued)
+	}
+}
+
+func TestNextIDWithFlags_NoField(t *testing.T) {
+	gen, _ := NewGenerator(Config{Version: Version0})
+	if _, err := gen.NextIDWithFlags(1); !errors.Is(err, ErrInvalidLayout) {
+		t.Errorf("Expected ErrInvalidLayout, got %v", err)
+	}
+
+	id, _ := gen.NextID()
+	decoded, _ := Decode(id)
+	if _, ok := decoded.Flags(); ok {
+		t.Error("Expected no flags for a layout without a flags field")
+	}
+}
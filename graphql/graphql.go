@@ -0,0 +1,65 @@
+// Package graphql provides a snowflake ID scalar for gqlgen.
+//
+// IDs travel as decimal strings, since GraphQL clients commonly parse
+// numbers as IEEE 754 doubles and would silently round IDs above 2^53.
+// Bind a scalar to the function pair in gqlgen.yml:
+//
+//	models:
+//	  SnowflakeID:
+//	    model: github.com/samarthasthan/snowflake/graphql.ID
+//
+// The package has no gqlgen dependency: Marshaler has the same method set
+// as gqlgen's graphql.Marshaler, so generated code accepts it directly.
+package graphql
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/samarthasthan/snowflake"
+)
+
+// Marshaler writes a GraphQL value. It matches gqlgen's graphql.Marshaler.
+type Marshaler interface {
+	MarshalGQL(w io.Writer)
+}
+
+type idMarshaler snowflake.ID
+
+func (id idMarshaler) MarshalGQL(w io.Writer) {
+	io.WriteString(w, strconv.Quote(snowflake.ID(id).String()))
+}
+
+// MarshalID encodes id as a quoted decimal string
+func MarshalID(id snowflake.ID) Marshaler {
+	return idMarshaler(id)
+}
+
+// UnmarshalID decodes an ID from a decimal string. Integer literals are
+// also accepted for clients that send IDs as numbers, provided they are
+// non-negative.
+func UnmarshalID(v any) (snowflake.ID, error) {
+	switch v := v.(type) {
+	case string:
+		return snowflake.ParseID(v)
+	case json.Number:
+		return snowflake.ParseID(v.String())
+	case int:
+		return fromInt64(int64(v))
+	case int64:
+		return fromInt64(v)
+	case uint64:
+		return snowflake.ID(v), nil
+	default:
+		return 0, fmt.Errorf("%w: unsupported GraphQL value %T", snowflake.ErrInvalidID, v)
+	}
+}
+
+func fromInt64(n int64) (snowflake.ID, error) {
+	if n < 0 {
+		return 0, fmt.Errorf("%w: %d", snowflake.ErrInvalidID, n)
+	}
+	return snowflake.ID(n), nil
+}
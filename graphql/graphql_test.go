@@ -0,0 +1,50 @@
+package graphql
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/samarthasthan/snowflake"
+)
+
+func TestMarshalID(t *testing.T) {
+	var buf bytes.Buffer
+	MarshalID(snowflake.ID(1<<60 + 1)).MarshalGQL(&buf)
+
+	if got := buf.String(); got != `"1152921504606846977"` {
+		t.Errorf("Expected quoted decimal, got %s", got)
+	}
+}
+
+func TestUnmarshalID(t *testing.T) {
+	tests := []struct {
+		name string
+		in   any
+		want snowflake.ID
+		ok   bool
+	}{
+		{"string", "1152921504606846977", 1<<60 + 1, true},
+		{"json number", json.Number("42"), 42, true},
+		{"int64", int64(42), 42, true},
+		{"negative", int64(-1), 0, false},
+		{"not a number", "abc", 0, false},
+		{"float", 1.5, 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := UnmarshalID(tt.in)
+			if !tt.ok {
+				if !errors.Is(err, snowflake.ErrInvalidID) {
+					t.Errorf("Expected ErrInvalidID, got %v", err)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Errorf("Expected %d, got %d (%v)", tt.want, got, err)
+			}
+		})
+	}
+}
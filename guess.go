@@ -0,0 +1,95 @@
+package snowflake
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// EpochGuess is the result of GuessEpoch
+type EpochGuess struct {
+	// Epoch is the most plausible epoch: a named epoch if one fits,
+	// otherwise the roundest date that does
+	Epoch time.Time
+
+	// Name is the named epoch chosen, or "" for a date
+	Name string
+
+	// Earliest and Latest bound every epoch consistent with the sample
+	// and the creation window
+	Earliest, Latest time.Time
+}
+
+// GuessEpoch infers the epoch of a foreign ID scheme from a sample of its
+// IDs, known to have been created between from and to. layout supplies
+// the field widths and time unit; its epoch is ignored.
+//
+// Each ID's raw timestamp narrows the epochs that would place it inside
+// the window. Among those, a well-known epoch (see NamedEpochs) wins,
+// then the first January 1st, first of a month or midnight UTC, in that
+// order, since custom epochs are nearly always round dates. Wider samples
+// and tighter windows narrow the result; check Earliest and Latest
+// before trusting it.
+func GuessEpoch(ids []uint64, layout *VersionLayout, from, to time.Time) (EpochGuess, error) {
+	if len(ids) == 0 {
+		return EpochGuess{}, errors.New("no IDs to guess from")
+	}
+	if !from.Before(to) {
+		return EpochGuess{}, fmt.Errorf("%w: %s is not before %s", ErrInvalidRange,
+			from.Format(time.RFC3339), to.Format(time.RFC3339))
+	}
+
+	probe := layout.clone()
+	probe.Epoch = EpochUnix
+	if err := probe.validate(); err != nil {
+		return EpochGuess{}, err
+	}
+
+	lo, hi := ^uint64(0), uint64(0)
+	for _, id := range ids {
+		ts := probe.decode(id).Timestamp
+		lo, hi = min(lo, ts), max(hi, ts)
+	}
+
+	// from <= epoch + ts*unit < to must hold for every timestamp
+	earliest := from.Add(-probe.timeOf(lo).Sub(EpochUnix))
+	latest := to.Add(-probe.timeOf(hi).Sub(EpochUnix))
+	if latest.Before(earliest) {
+		return EpochGuess{}, fmt.Errorf("%w: IDs span %s, longer than the window",
+			ErrInvalidRange, probe.timeOf(hi).Sub(probe.timeOf(lo)))
+	}
+
+	guess := EpochGuess{Earliest: earliest.UTC(), Latest: latest.UTC()}
+	for _, e := range namedEpochs {
+		if !e.Epoch.Before(earliest) && !e.Epoch.After(latest) {
+			guess.Epoch, guess.Name = e.Epoch, e.Name
+			return guess, nil
+		}
+	}
+	guess.Epoch = roundestTime(guess.Earliest, guess.Latest)
+	return guess, nil
+}
+
+// roundestTime returns the first January 1st, first of a month or UTC
+// midnight in [lo, hi], in that order of preference, or the midpoint if
+// the range holds none. lo and hi must be in UTC.
+func roundestTime(lo, hi time.Time) time.Time {
+	candidates := []struct {
+		start               time.Time
+		years, months, days int
+	}{
+		{time.Date(lo.Year(), 1, 1, 0, 0, 0, 0, time.UTC), 1, 0, 0},
+		{time.Date(lo.Year(), lo.Month(), 1, 0, 0, 0, 0, time.UTC), 0, 1, 0},
+		{time.Date(lo.Year(), lo.Month(), lo.Day(), 0, 0, 0, 0, time.UTC), 0, 0, 1},
+	}
+	for _, c := range candidates {
+		t := c.start
+		if t.Before(lo) {
+			t = t.AddDate(c.years, c.months, c.days)
+		}
+		if !t.After(hi) {
+			return t
+		}
+	}
+	return lo.Add(hi.Sub(lo) / 2)
+}
@@ -0,0 +1,54 @@
+package snowflake
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestGuessEpoch(t *testing.T) {
+	twitter := TwitterLayout()
+	window := func(epoch time.Time, layout *VersionLayout, from, to time.Time) []uint64 {
+		var ids []uint64
+		for at := from; at.Before(to); at = at.Add(to.Sub(from) / 10) {
+			ts := uint64(at.Sub(epoch) / layout.TimeUnit)
+			ids = append(ids, layout.encode(ts, 3, 0))
+		}
+		return ids
+	}
+
+	tests := []struct {
+		name     string
+		layout   *VersionLayout
+		epoch    time.Time
+		wantName string
+	}{
+		{"named epoch", twitter, EpochTwitter2010, "twitter2010"},
+		{"new year", twitter, time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC), ""},
+		{"first of month", SonyflakeLayout(), time.Date(2017, 6, 1, 0, 0, 0, 0, time.UTC), ""},
+	}
+	from := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	to := from.Add(48 * time.Hour)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// The sample covers most of the window, leaving a few hours slack
+			ids := window(tt.epoch, tt.layout, from.Add(2*time.Hour), to.Add(-2*time.Hour))
+			guess, err := GuessEpoch(ids, tt.layout, from, to)
+			if err != nil {
+				t.Fatalf("GuessEpoch failed: %v", err)
+			}
+			if !guess.Epoch.Equal(tt.epoch) || guess.Name != tt.wantName {
+				t.Errorf("Expected %s (%q), got %+v", tt.epoch, tt.wantName, guess)
+			}
+			if guess.Earliest.After(tt.epoch) || guess.Latest.Before(tt.epoch) {
+				t.Errorf("Expected [%s, %s] to contain %s", guess.Earliest, guess.Latest, tt.epoch)
+			}
+		})
+	}
+
+	ids := window(EpochTwitter2010, twitter, from, from.Add(72*time.Hour))
+	if _, err := GuessEpoch(ids, twitter, from, to); !errors.Is(err, ErrInvalidRange) {
+		t.Errorf("Expected ErrInvalidRange for IDs spanning more than the window, got %v", err)
+	}
+}
@@ -0,0 +1,27 @@
+package snowflake
+
+import "time"
+
+// Hooks are optional callbacks for observing generator events. They run
+// synchronously on the calling goroutine and should return quickly; the
+// wait hooks run while the generator's lock is held.
+type Hooks struct {
+	// OnPreEpoch is called by NewGenerator when the clock reads earlier
+	// than the layout's epoch and Config.AllowPreEpoch is set
+	OnPreEpoch func(now, epoch time.Time)
+
+	// OnOverflowWait is called after a call waited for the next time unit
+	// because the sequence was exhausted, with the time spent waiting
+	OnOverflowWait func(waited time.Duration)
+
+	// OnRollbackWait is called after a call waited for the clock to catch
+	// up with the last issued timestamp, with the time spent waiting
+	OnRollbackWait func(waited time.Duration)
+
+	// OnLifetimeWarning is called once per generator when its timestamps
+	// pass Config.LifetimeWarning, with the fraction of the time range used
+	// and the time the range runs out. It runs from NewGenerator if the
+	// clock is already past the threshold, otherwise from the ID call that
+	// crosses it, while the generator's lock is held.
+	OnLifetimeWarning func(used float64, exhausted time.Time)
+}
@@ -0,0 +1,117 @@
+package snowflake
+
+import (
+	"testing"
+	"time"
+)
+
+func TestHooks_OverflowWait(t *testing.T) {
+	// The clock advances one millisecond every 300 reads, so the 257th ID
+	// exhausts the sequence and waits
+	base := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	reads := 0
+	clock := ClockFunc(func() (time.Time, error) {
+		reads++
+		return base.Add(time.Duration(reads/300) * time.Millisecond), nil
+	})
+
+	var waits []time.Duration
+	gen, _ := NewGenerator(Config{
+		Version: Version0,
+		Clock:   clock,
+		Hooks:   Hooks{OnOverflowWait: func(d time.Duration) { waits = append(waits, d) }},
+	})
+
+	if _, err := gen.NextIDs(300); err != nil {
+		t.Fatalf("Failed to generate batch: %v", err)
+	}
+	if len(waits) != 1 || waits[0] <= 0 {
+		t.Errorf("Expected one positive overflow wait, got %v", waits)
+	}
+}
+
+func TestHooks_RollbackWait(t *testing.T) {
+	base := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	// NewGenerator and the first ID read base; the second ID then sees the
+	// clock step back and waits for it to return
+	readings := []time.Time{base, base, base.Add(-time.Millisecond), base.Add(-time.Millisecond), base}
+	clock := ClockFunc(func() (time.Time, error) {
+		t := readings[0]
+		if len(readings) > 1 {
+			readings = readings[1:]
+		}
+		return t, nil
+	})
+
+	var waits []time.Duration
+	gen, _ := NewGenerator(Config{
+		Version: Version0,
+		Clock:   clock,
+		Hooks:   Hooks{OnRollbackWait: func(d time.Duration) { waits = append(waits, d) }},
+	})
+
+	for i := 0; i < 2; i++ {
+		if _, err := gen.NextID(); err != nil {
+			t.Fatalf("Failed to generate ID: %v", err)
+		}
+	}
+	if len(waits) != 1 || waits[0] <= 0 {
+		t.Errorf("Expected one positive rollback wait, got %v", waits)
+	}
+}
+
+func TestHooks_OnLifetimeWarning(t *testing.T) {
+	layout := TwitterLayout()
+	at := func(fraction float64) time.Time {
+		return layout.timeOf(uint64(float64(layout.MaxTimestamp) * fraction))
+	}
+
+	now := at(0.4)
+	clock := ClockFunc(func() (time.Time, error) { return now, nil })
+	var warnings []float64
+	cfg := Config{
+		Layout:          layout,
+		Clock:           clock,
+		LifetimeWarning: 0.5,
+		Hooks: Hooks{OnLifetimeWarning: func(used float64, exhausted time.Time) {
+			if !exhausted.Equal(layout.ExhaustionTime()) {
+				t.Errorf("Expected exhaustion at %s, got %s", layout.ExhaustionTime(), exhausted)
+			}
+			warnings = append(warnings, used)
+		}},
+	}
+
+	gen, err := NewGenerator(cfg)
+	if err != nil {
+		t.Fatalf("Failed to create generator: %v", err)
+	}
+	gen.NextID()
+	if len(warnings) != 0 {
+		t.Fatalf("Expected no warning below the threshold, got %v", warnings)
+	}
+
+	now = at(0.6)
+	gen.NextID()
+	gen.NextID()
+	if len(warnings) != 1 || warnings[0] < 0.59 || warnings[0] > 0.61 {
+		t.Fatalf("Expected one warning at 0.6, got %v", warnings)
+	}
+
+	// Starting past the threshold warns from NewGenerator
+	warnings = nil
+	if gen, err = NewGenerator(cfg); err != nil {
+		t.Fatalf("Failed to create generator: %v", err)
+	}
+	if len(warnings) != 1 {
+		t.Fatalf("Expected a warning on creation, got %v", warnings)
+	}
+	gen.NextID()
+	if len(warnings) != 1 {
+		t.Errorf("Expected no further warnings, got %v", warnings)
+	}
+
+	cfg.LifetimeWarning = 1.5
+	if _, err := NewGenerator(cfg); err == nil {
+		t.Error("Expected error for a lifetime warning above 1")
+	}
+}
@@ -0,0 +1,64 @@
+package snowflake
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+)
+
+var ErrInvalidID = errors.New("invalid ID")
+
+// ID is a Snowflake ID. It implements flag.Value (and pflag.Value), so
+// binaries can accept IDs such as --starting-id directly.
+type ID uint64
+
+// ParseID parses a decimal ID
+func ParseID(s string) (ID, error) {
+	n, err := strconv.ParseUint(s, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidID, s)
+	}
+	return ID(n), nil
+}
+
+// String returns the ID in decimal
+func (id ID) String() string {
+	return strconv.FormatUint(uint64(id), 10)
+}
+
+// Set implements flag.Value
+func (id *ID) Set(s string) error {
+	parsed, err := ParseID(s)
+	if err != nil {
+		return err
+	}
+	*id = parsed
+	return nil
+}
+
+// Type implements pflag.Value
+func (id *ID) Type() string {
+	return "id"
+}
+
+// Float64Key returns the ID as a float64 for systems that only store
+// doubles. The mapping preserves order (a < b implies a.Float64Key() <=
+// b.Float64Key()) but is only exact below 2^53; above that, IDs within a
+// window of 2^(k-53) collapse to the same key, where k is the bit length
+// of the ID. Version0 IDs stay below 2^53 until mid-2030; a 63-bit ID is
+// only accurate to within 1024.
+func (id ID) Float64Key() float64 {
+	return float64(id)
+}
+
+// IDFromFloat64Key is the best-effort reverse of Float64Key. It returns
+// the ID exactly when the key is below 2^53 and otherwise the nearest ID
+// the key can represent, which may not be one that was ever issued.
+func IDFromFloat64Key(f float64) (ID, error) {
+	// 1<<64 is the smallest float64 too large for a uint64
+	if math.IsNaN(f) || f < 0 || f >= 1<<64 || f != math.Trunc(f) {
+		return 0, fmt.Errorf("%w: float64 key %v", ErrInvalidID, f)
+	}
+	return ID(f), nil
+}
@@ -0,0 +1,43 @@
+package snowflake
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestFloat64Key_Monotone(t *testing.T) {
+	ids := []ID{0, 1, 334076313601793, 1<<53 - 1, 1 << 53, 1<<53 + 1, 1<<62 + 12345, 1<<63 - 1, 1 << 63, math.MaxUint64}
+	for i := 1; i < len(ids); i++ {
+		if ids[i-1].Float64Key() > ids[i].Float64Key() {
+			t.Fatalf("key(%d) > key(%d)", ids[i-1], ids[i])
+		}
+	}
+}
+
+func TestFloat64Key_RoundTrip(t *testing.T) {
+	for _, id := range []ID{0, 1, 334076313601793, 1<<53 - 1} {
+		got, err := IDFromFloat64Key(id.Float64Key())
+		if err != nil || got != id {
+			t.Fatalf("round trip %d: got %d, %v", id, got, err)
+		}
+	}
+
+	// Above 2^53 the reverse is only approximate
+	id := ID(1<<62 + 12345)
+	got, err := IDFromFloat64Key(id.Float64Key())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := int64(got - id); diff < -1024 || diff > 1024 {
+		t.Fatalf("approximate round trip %d: got %d", id, got)
+	}
+}
+
+func TestIDFromFloat64Key_Invalid(t *testing.T) {
+	for _, f := range []float64{math.NaN(), math.Inf(1), -1, 0.5, 1 << 64} {
+		if _, err := IDFromFloat64Key(f); !errors.Is(err, ErrInvalidID) {
+			t.Fatalf("IDFromFloat64Key(%v): expected ErrInvalidID, got %v", f, err)
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package snowflake
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestImportCheck(t *testing.T) {
+	layout, _ := LookupLayout(Version0)
+	since := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+	ts := func(at time.Time) uint64 { return uint64(at.Sub(layout.Epoch) / layout.TimeUnit) }
+
+	ours := layout.encode(ts(since.Add(time.Hour)), 1, 0)      // our node, our period
+	otherNode := layout.encode(ts(since.Add(time.Hour)), 9, 0) // node we never use
+	before := layout.encode(ts(since.Add(-time.Hour)), 1, 0)   // before we started
+	otherVersion := uint64(6) << 61
+
+	ids := []uint64{ours, otherNode, before, otherVersion, otherNode}
+	conflicts, err := ImportCheck(ids, ImportPolicy{Version: Version0, Nodes: []uint64{1, 2}, Since: since})
+	if err != nil {
+		t.Fatalf("ImportCheck failed: %v", err)
+	}
+
+	if len(conflicts) != 2 {
+		t.Fatalf("Expected 2 conflicts, got %+v", conflicts)
+	}
+	if conflicts[0].Index != 0 || !errors.Is(conflicts[0].Err, ErrImportOverlap) {
+		t.Errorf("Expected overlap at index 0, got %+v", conflicts[0])
+	}
+	if conflicts[1].Index != 4 || !errors.Is(conflicts[1].Err, ErrImportDuplicate) {
+		t.Errorf("Expected duplicate at index 4, got %+v", conflicts[1])
+	}
+
+	// Without node or time bounds every Version0 ID overlaps
+	conflicts, _ = ImportCheck([]uint64{ours, otherNode, before, otherVersion}, ImportPolicy{Version: Version0})
+	if len(conflicts) != 3 {
+		t.Errorf("Expected 3 conflicts, got %+v", conflicts)
+	}
+}
+
+func TestImportCheck_Versionless(t *testing.T) {
+	layout := TwitterLayout()
+	layout.Version = 6
+	withTestLayout(t, layout)
+
+	since := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+	ts := func(at time.Time) uint64 { return uint64(at.Sub(layout.Epoch) / layout.TimeUnit) }
+	ours := layout.encode(ts(since.Add(time.Hour)), 1, 0)
+	before := layout.encode(ts(since.Add(-time.Hour)), 1, 0)
+
+	conflicts, err := ImportCheck([]uint64{ours, before}, ImportPolicy{Version: 6, Since: since})
+	if err != nil {
+		t.Fatalf("ImportCheck failed: %v", err)
+	}
+	if len(conflicts) != 1 || conflicts[0].Index != 0 || !errors.Is(conflicts[0].Err, ErrImportOverlap) {
+		t.Errorf("Expected overlap at index 0, got %+v", conflicts)
+	}
+}
+
+func TestImportCheck_InvalidPolicy(t *testing.T) {
+	if _, err := ImportCheck(nil, ImportPolicy{Version: 7}); !errors.Is(err, ErrInvalidVersion) {
+		t.Errorf("Expected ErrInvalidVersion, got %v", err)
+	}
+
+	now := time.Now()
+	if _, err := ImportCheck(nil, ImportPolicy{Since: now, Until: now.Add(-time.Hour)}); !errors.Is(err, ErrInvalidRange) {
+		t.Errorf("Expected ErrInvalidRange, got %v", err)
+	}
+}
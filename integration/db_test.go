@@ -0,0 +1,285 @@
+//go:build integration
+
+package integration
+
+import (
+	"database/sql"
+	"math/rand"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	_ "github.com/go-sql-driver/mysql"
+	_ "github.com/lib/pq"
+
+	"github.com/samarthasthan/snowflake"
+)
+
+const (
+	defaultCount = 1_000_000
+	insertBatch  = 1000
+	nodes        = 4
+)
+
+// dialect describes how one database stores IDs. Signed databases have no
+// unsigned 64-bit column, so IDs are stored as their int64 bit pattern.
+type dialect struct {
+	name        string
+	driver      string
+	dsnEnv      string
+	createTable string
+	signed      bool
+	placeholder func(i int) string
+}
+
+var dialects = []dialect{
+	{
+		name:        "postgres",
+		driver:      "postgres",
+		dsnEnv:      "SNOWFLAKE_POSTGRES_DSN",
+		createTable: "CREATE TABLE snowflake_ids (id BIGINT PRIMARY KEY)",
+		signed:      true,
+		placeholder: func(i int) string { return "$" + strconv.Itoa(i+1) },
+	},
+	{
+		name:        "mysql",
+		driver:      "mysql",
+		dsnEnv:      "SNOWFLAKE_MYSQL_DSN",
+		createTable: "CREATE TABLE snowflake_ids (id BIGINT UNSIGNED PRIMARY KEY)",
+		placeholder: func(int) string { return "?" },
+	},
+}
+
+// highBitVersion is a user version whose prefix sets bit 63, used to probe
+// how each database handles IDs beyond the signed range.
+const highBitVersion snowflake.Version = 7
+
+func init() {
+	layout, err := snowflake.NewLayoutBuilder().
+		Version(highBitVersion, 3).
+		Time(45, time.Millisecond, snowflake.EpochY2026).
+		Field("node", 8).
+		Sequence(8).
+		Build()
+	if err != nil {
+		panic(err)
+	}
+	snowflake.MustRegisterLayout(layout)
+}
+
+func TestDatabases(t *testing.T) {
+	for _, d := range dialects {
+		t.Run(d.name, func(t *testing.T) {
+			dsn := os.Getenv(d.dsnEnv)
+			if dsn == "" {
+				t.Skipf("%s not set", d.dsnEnv)
+			}
+			db := openDB(t, d, dsn)
+
+			t.Run("OrderingAndUniqueness", func(t *testing.T) {
+				resetTable(t, db, d)
+				testOrderingAndUniqueness(t, db, d)
+			})
+			t.Run("UnsignedRange", func(t *testing.T) {
+				resetTable(t, db, d)
+				testUnsignedRange(t, db, d)
+			})
+		})
+	}
+}
+
+// testOrderingAndUniqueness inserts IDs from several nodes in random order
+// and checks the primary key accepted every one of them and that the
+// database orders them exactly as they were generated.
+func testOrderingAndUniqueness(t *testing.T, db *sql.DB, d dialect) {
+	perNode := idCount(t) / nodes
+	generated := make([][]uint64, nodes)
+
+	var wg sync.WaitGroup
+	errs := make(chan error, nodes)
+	for node := range nodes {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			gen, err := snowflake.NewGenerator(snowflake.Config{
+				Version: snowflake.Version0,
+				NodeID:  uint64(node),
+			})
+			if err != nil {
+				errs <- err
+				return
+			}
+			generated[node], err = gen.NextIDs(perNode)
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("Failed to generate IDs: %v", err)
+		}
+	}
+
+	var all []uint64
+	for _, ids := range generated {
+		all = append(all, ids...)
+	}
+	rand.Shuffle(len(all), func(i, j int) { all[i], all[j] = all[j], all[i] })
+	insertIDs(t, db, d, all)
+
+	stored := selectIDs(t, db, d)
+	if len(stored) != len(all) {
+		t.Fatalf("Expected %d rows, got %d", len(all), len(stored))
+	}
+
+	next := make([]int, nodes)
+	for i, id := range stored {
+		if i > 0 && id <= stored[i-1] {
+			t.Fatalf("Row %d: %d not greater than %d", i, id, stored[i-1])
+		}
+		decoded, err := snowflake.Decode(id)
+		if err != nil {
+			t.Fatalf("Failed to decode stored ID %d: %v", id, err)
+		}
+		node := decoded.NodeID
+		if want := generated[node][next[node]]; id != want {
+			t.Fatalf("Node %d: expected %d at position %d, got %d", node, want, next[node], id)
+		}
+		next[node]++
+	}
+}
+
+// testUnsignedRange stores an ID with bit 63 set next to a Version0 ID.
+// Unsigned columns keep the numeric order; signed columns round-trip the
+// bit pattern but sort it before every ID that leaves bit 63 clear.
+func testUnsignedRange(t *testing.T, db *sql.DB, d dialect) {
+	low := nextID(t, snowflake.Version0)
+	high := nextID(t, highBitVersion)
+	if high>>63 != 1 {
+		t.Fatalf("Expected bit 63 set in %d", high)
+	}
+
+	insertIDs(t, db, d, []uint64{low, high})
+	stored := selectIDs(t, db, d)
+
+	want := []uint64{low, high}
+	if d.signed {
+		want = []uint64{high, low}
+	}
+	if len(stored) != 2 || stored[0] != want[0] || stored[1] != want[1] {
+		t.Errorf("Expected %v, got %v", want, stored)
+	}
+}
+
+func openDB(t *testing.T, d dialect, dsn string) *sql.DB {
+	t.Helper()
+	db, err := sql.Open(d.driver, dsn)
+	if err != nil {
+		t.Fatalf("Failed to open %s: %v", d.name, err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	deadline := time.Now().Add(time.Minute)
+	for {
+		err := db.Ping()
+		if err == nil {
+			return db
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("Failed to reach %s: %v", d.name, err)
+		}
+		time.Sleep(time.Second)
+	}
+}
+
+func resetTable(t *testing.T, db *sql.DB, d dialect) {
+	t.Helper()
+	if _, err := db.Exec("DROP TABLE IF EXISTS snowflake_ids"); err != nil {
+		t.Fatalf("Failed to drop table: %v", err)
+	}
+	if _, err := db.Exec(d.createTable); err != nil {
+		t.Fatalf("Failed to create table: %v", err)
+	}
+}
+
+func insertIDs(t *testing.T, db *sql.DB, d dialect, ids []uint64) {
+	t.Helper()
+	for start := 0; start < len(ids); start += insertBatch {
+		batch := ids[start:min(start+insertBatch, len(ids))]
+
+		values := make([]string, len(batch))
+		args := make([]any, len(batch))
+		for i, id := range batch {
+			values[i] = "(" + d.placeholder(i) + ")"
+			if d.signed {
+				args[i] = int64(id)
+			} else {
+				args[i] = id
+			}
+		}
+
+		query := "INSERT INTO snowflake_ids (id) VALUES " + strings.Join(values, ",")
+		if _, err := db.Exec(query, args...); err != nil {
+			t.Fatalf("Failed to insert batch at %d: %v", start, err)
+		}
+	}
+}
+
+func selectIDs(t *testing.T, db *sql.DB, d dialect) []uint64 {
+	t.Helper()
+	rows, err := db.Query("SELECT id FROM snowflake_ids ORDER BY id")
+	if err != nil {
+		t.Fatalf("Failed to select IDs: %v", err)
+	}
+	defer rows.Close()
+
+	var ids []uint64
+	for rows.Next() {
+		var id uint64
+		if d.signed {
+			var signed int64
+			err = rows.Scan(&signed)
+			id = uint64(signed)
+		} else {
+			err = rows.Scan(&id)
+		}
+		if err != nil {
+			t.Fatalf("Failed to scan ID: %v", err)
+		}
+		ids = append(ids, id)
+	}
+	if err := rows.Err(); err != nil {
+		t.Fatalf("Failed to read rows: %v", err)
+	}
+	return ids
+}
+
+func nextID(t *testing.T, v snowflake.Version) uint64 {
+	t.Helper()
+	gen, err := snowflake.NewGenerator(snowflake.Config{Version: v})
+	if err != nil {
+		t.Fatalf("Failed to create %s generator: %v", v, err)
+	}
+	id, err := gen.NextID()
+	if err != nil {
+		t.Fatalf("Failed to generate ID: %v", err)
+	}
+	return id
+}
+
+func idCount(t *testing.T) int {
+	t.Helper()
+	s := os.Getenv("SNOWFLAKE_INTEGRATION_N")
+	if s == "" {
+		return defaultCount
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil || n < nodes {
+		t.Fatalf("Invalid SNOWFLAKE_INTEGRATION_N %q", s)
+	}
+	return n
+}
@@ -0,0 +1,14 @@
+// Package integration holds the database integration suite. It lives in its
+// own module so the driver dependencies never reach users of the root
+// package.
+//
+// The tests are behind the "integration" build tag and connect to the
+// databases named by SNOWFLAKE_POSTGRES_DSN and SNOWFLAKE_MYSQL_DSN:
+//
+//	docker compose -f integration/docker-compose.yml up -d
+//	make integration
+//
+// Set SNOWFLAKE_INTEGRATION_N to change how many IDs are inserted
+// (default 1,000,000). Point the DSNs at your own servers to check the
+// database versions you run in production.
+package integration
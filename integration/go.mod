@@ -0,0 +1,13 @@
+module github.com/samarthasthan/snowflake/integration
+
+go 1.25.3
+
+require (
+	github.com/go-sql-driver/mysql v1.9.3
+	github.com/lib/pq v1.10.9
+	github.com/samarthasthan/snowflake v0.0.0
+)
+
+require filippo.io/edwards25519 v1.1.0 // indirect
+
+replace github.com/samarthasthan/snowflake => ../
@@ -0,0 +1,241 @@
+package snowflake
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var ErrInvalidLayout = errors.New("invalid layout")
+
+// Field is an application-defined bit field in a custom layout. Custom
+// fields sit between the time and node fields, most significant first:
+//
+//	[version][time][fields...][node][sequence]
+type Field struct {
+	Name string
+	Bits uint8
+}
+
+// reservedFieldNames are the built-in field names
+var reservedFieldNames = map[string]bool{
+	"version":  true,
+	"time":     true,
+	"node":     true,
+	"sequence": true,
+}
+
+// validate checks that the layout's fields fit in 64 bits, that the version
+// number fits its field and that the maximums match field widths.
+//
+// Versioned layouts must fill all 64 bits so the version sits at the top.
+// Versionless layouts (VersionBits == 0) may leave high bits unused, which
+// are then always zero; their Version only identifies the layout locally.
+func (l *VersionLayout) validate() error {
+	total := int(l.VersionBits) + int(l.TimeBits) + int(l.NodeBits) + int(l.SequenceBits)
+	seen := make(map[string]bool, len(l.Fields))
+	for _, f := range l.Fields {
+		if f.Name == "" || f.Bits == 0 {
+			return fmt.Errorf("%w: version %d has an unnamed or empty field", ErrInvalidLayout, l.Version)
+		}
+		if reservedFieldNames[f.Name] || seen[f.Name] {
+			return fmt.Errorf("%w: version %d field name %q is reserved or duplicated", ErrInvalidLayout, l.Version, f.Name)
+		}
+		if f.Name == SourceField && (f.Bits < minSourceBits || f.Bits > maxSourceBits) {
+			return fmt.Errorf("%w: version %d source field must be %d–%d bits", ErrInvalidLayout, l.Version, minSourceBits, maxSourceBits)
+		}
+		if f.Name == TombstoneField && f.Bits != 1 {
+			return fmt.Errorf("%w: version %d tombstone field must be 1 bit", ErrInvalidLayout, l.Version)
+		}
+		if f.Name == TenantField && f.Bits > maxTenantBits {
+			return fmt.Errorf("%w: version %d tenant field exceeds %d bits", ErrInvalidLayout, l.Version, maxTenantBits)
+		}
+		if f.Name == ChildField && f.Bits > maxChildBits {
+			return fmt.Errorf("%w: version %d child field exceeds %d bits", ErrInvalidLayout, l.Version, maxChildBits)
+		}
+		seen[f.Name] = true
+		total += int(f.Bits)
+	}
+
+	if total > 64 || (l.VersionBits > 0 && total != 64) {
+		return fmt.Errorf("%w: version %d fields span %d bits, want 64", ErrInvalidLayout, l.Version, total)
+	}
+	if _, err := strconv.ParseUint(strings.TrimPrefix(strings.ToLower(l.Name), "v"), 10, 64); err == nil {
+		return fmt.Errorf("%w: version %d name %q looks like a version number", ErrInvalidLayout, l.Version, l.Name)
+	}
+	if l.VersionBits > 0 && uint64(l.Version) > mask(l.VersionBits) {
+		return fmt.Errorf("%w: version %d does not fit in %d bits", ErrInvalidLayout, l.Version, l.VersionBits)
+	}
+	if l.TimeBits == 0 || l.TimeUnit <= 0 {
+		return fmt.Errorf("%w: version %d needs time bits and a positive time unit", ErrInvalidLayout, l.Version)
+	}
+	if l.MaxTimestamp != mask(l.TimeBits) || l.MaxNodeID != mask(l.NodeBits) || l.MaxSequence != mask(l.SequenceBits) {
+		return fmt.Errorf("%w: version %d maximums do not match field widths", ErrInvalidLayout, l.Version)
+	}
+	return nil
+}
+
+// Validate checks that the layout is well formed, as RegisterLayout,
+// NewGenerator and the JSON and YAML decoders do
+func (l *VersionLayout) Validate() error {
+	return l.validate()
+}
+
+// MaxIDsPerSecond returns the most IDs a single node can issue per second:
+// a full sequence every time unit
+func (l *VersionLayout) MaxIDsPerSecond() float64 {
+	return float64(l.MaxSequence+1) * float64(time.Second) / float64(l.TimeUnit)
+}
+
+// MaxNodes returns the number of distinct node IDs
+func (l *VersionLayout) MaxNodes() uint64 {
+	return l.MaxNodeID + 1
+}
+
+// clone returns a deep copy of the layout
+func (l *VersionLayout) clone() *VersionLayout {
+	c := *l
+	c.Fields = append([]Field(nil), l.Fields...)
+	return &c
+}
+
+// timeShift returns the bit offset of the time field
+func (l *VersionLayout) timeShift() uint8 {
+	shift := l.SequenceBits + l.NodeBits
+	for _, f := range l.Fields {
+		shift += f.Bits
+	}
+	return shift
+}
+
+// nodeShift returns the bit offset of the node field
+func (l *VersionLayout) nodeShift() uint8 {
+	if l.NodeLast {
+		return 0
+	}
+	return l.SequenceBits
+}
+
+// sequenceShift returns the bit offset of the sequence field
+func (l *VersionLayout) sequenceShift() uint8 {
+	if l.NodeLast {
+		return l.NodeBits
+	}
+	return 0
+}
+
+// versionShift returns the bit offset of the version field
+func (l *VersionLayout) versionShift() uint8 {
+	return l.timeShift() + l.TimeBits
+}
+
+// field returns the bit offset and width of the named custom field
+func (l *VersionLayout) field(name string) (shift, bits uint8, ok bool) {
+	shift = l.timeShift()
+	for _, f := range l.Fields {
+		shift -= f.Bits
+		if f.Name == name {
+			return shift, f.Bits, true
+		}
+	}
+	return 0, 0, false
+}
+
+// fieldsMask returns the bits covered by custom fields
+func (l *VersionLayout) fieldsMask() uint64 {
+	var m uint64
+	for _, f := range l.Fields {
+		shift, bits, _ := l.field(f.Name)
+		m |= mask(bits) << shift
+	}
+	return m
+}
+
+// prefix returns the layout's version field in position, or zero for
+// versionless layouts
+func (l *VersionLayout) prefix() uint64 {
+	if l.VersionBits == 0 {
+		return 0
+	}
+	return uint64(l.Version) << l.versionShift()
+}
+
+// matches reports whether id carries this layout's version number.
+// Versionless layouts never match, since their IDs carry no version.
+func (l *VersionLayout) matches(id uint64) bool {
+	return l.VersionBits > 0 && id>>l.versionShift() == uint64(l.Version)
+}
+
+// positive reports whether the top bit of the layout's IDs is always zero:
+// it is either unused, or the top bit of a version field that is zero
+func (l *VersionLayout) positive() bool {
+	if l.VersionBits > 0 {
+		return uint64(l.Version)>>(l.VersionBits-1) == 0
+	}
+	return l.versionShift() < 64
+}
+
+// encode packs the components into an ID, with custom fields zero, without
+// validating them
+func (l *VersionLayout) encode(timestamp, nodeID, sequence uint64) uint64 {
+	return l.prefix() |
+		(timestamp << l.timeShift()) |
+		(nodeID << l.nodeShift()) |
+		(sequence << l.sequenceShift())
+}
+
+// decode splits an ID into its components without validating them
+func (l *VersionLayout) decode(id uint64) *DecodedID {
+	timestamp := (id >> l.timeShift()) & l.MaxTimestamp
+
+	decoded := &DecodedID{
+		Version:   l.Version,
+		Timestamp: timestamp,
+		NodeID:    (id >> l.nodeShift()) & l.MaxNodeID,
+		Sequence:  (id >> l.sequenceShift()) & l.MaxSequence,
+		Time:      l.timeOf(timestamp),
+	}
+
+	if len(l.Fields) > 0 {
+		decoded.Fields = make(map[string]uint64, len(l.Fields))
+		for _, f := range l.Fields {
+			shift, bits, _ := l.field(f.Name)
+			decoded.Fields[f.Name] = (id >> shift) & mask(bits)
+		}
+	}
+
+	return decoded
+}
+
+// DecodeFields decodes id with layout into a map of named field values,
+// covering the built-in fields ("version", "time", "node", "sequence")
+// and any custom fields. "version" is omitted for versionless layouts.
+func DecodeFields(id uint64, layout *VersionLayout) (map[string]uint64, error) {
+	decoded, err := DecodeWithLayout(id, layout)
+	if err != nil {
+		return nil, err
+	}
+
+	fields := map[string]uint64{
+		"time":     decoded.Timestamp,
+		"node":     decoded.NodeID,
+		"sequence": decoded.Sequence,
+	}
+	if layout.VersionBits > 0 {
+		fields["version"] = uint64(layout.Version)
+	}
+	for name, value := range decoded.Fields {
+		fields[name] = value
+	}
+	return fields, nil
+}
+
+// mask returns a mask of the given number of low bits
+func mask(bits uint8) uint64 {
+	if bits >= 64 {
+		return ^uint64(0)
+	}
+	return (1 << bits) - 1
+}
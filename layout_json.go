@@ -0,0 +1,130 @@
+package snowflake
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// layoutJSON is the machine-readable layout spec shared with other-language
+// implementations
+type layoutJSON struct {
+	Version    Version           `json:"version"`
+	Name       string            `json:"name,omitempty"`
+	Epoch      time.Time         `json:"epoch"`
+	TimeUnit   string            `json:"time_unit"`
+	TimeUnitNS int64             `json:"time_unit_ns"`
+	Fields     []layoutFieldJSON `json:"fields"`
+}
+
+// layoutFieldJSON describes one bit field, MSB first. Offset is the shift
+// of the field's least significant bit.
+type layoutFieldJSON struct {
+	Name   string `json:"name"`
+	Bits   uint8  `json:"bits"`
+	Offset uint8  `json:"offset"`
+}
+
+// MarshalJSON encodes the layout as a machine-readable spec listing each
+// field's width and bit offset, the epoch and the time unit
+func (l VersionLayout) MarshalJSON() ([]byte, error) {
+	fields := []layoutFieldJSON{
+		{Name: "version", Bits: l.VersionBits, Offset: l.versionShift()},
+		{Name: "time", Bits: l.TimeBits, Offset: l.timeShift()},
+	}
+	for _, f := range l.Fields {
+		shift, _, _ := l.field(f.Name)
+		fields = append(fields, layoutFieldJSON{Name: f.Name, Bits: f.Bits, Offset: shift})
+	}
+	node := layoutFieldJSON{Name: "node", Bits: l.NodeBits, Offset: l.nodeShift()}
+	sequence := layoutFieldJSON{Name: "sequence", Bits: l.SequenceBits, Offset: l.sequenceShift()}
+	if l.NodeLast {
+		fields = append(fields, sequence, node)
+	} else {
+		fields = append(fields, node, sequence)
+	}
+
+	return json.Marshal(layoutJSON{
+		Version:    l.Version,
+		Name:       l.Name,
+		Epoch:      l.Epoch.UTC(),
+		TimeUnit:   l.TimeUnit.String(),
+		TimeUnitNS: int64(l.TimeUnit),
+		Fields:     fields,
+	})
+}
+
+// UnmarshalJSON decodes and validates a spec written by MarshalJSON. Fields
+// must be listed MSB first, and their offsets must agree with that order,
+// so a hand-edited spec cannot contradict itself. time_unit_ns takes
+// precedence over time_unit.
+func (l *VersionLayout) UnmarshalJSON(data []byte) error {
+	var spec layoutJSON
+	if err := json.Unmarshal(data, &spec); err != nil {
+		return err
+	}
+
+	unit := time.Duration(spec.TimeUnitNS)
+	if unit == 0 && spec.TimeUnit != "" {
+		var err error
+		if unit, err = time.ParseDuration(spec.TimeUnit); err != nil {
+			return fmt.Errorf("time_unit: %w", err)
+		}
+	}
+
+	layout := VersionLayout{
+		Version:  spec.Version,
+		Name:     spec.Name,
+		TimeUnit: unit,
+		Epoch:    spec.Epoch,
+	}
+	nodeIndex, sequenceIndex := -1, -1
+	for i, f := range spec.Fields {
+		switch f.Name {
+		case "version":
+			layout.VersionBits = f.Bits
+		case "time":
+			layout.TimeBits = f.Bits
+		case "node":
+			layout.NodeBits, nodeIndex = f.Bits, i
+		case "sequence":
+			layout.SequenceBits, sequenceIndex = f.Bits, i
+		default:
+			layout.Fields = append(layout.Fields, Field{Name: f.Name, Bits: f.Bits})
+		}
+	}
+	layout.NodeLast = layout.NodeBits > 0 && sequenceIndex >= 0 && sequenceIndex < nodeIndex
+	layout.MaxTimestamp = mask(layout.TimeBits)
+	layout.MaxNodeID = mask(layout.NodeBits)
+	layout.MaxSequence = mask(layout.SequenceBits)
+
+	if err := layout.validate(); err != nil {
+		return err
+	}
+
+	for _, f := range spec.Fields {
+		var want uint8
+		switch f.Name {
+		case "version":
+			if f.Bits == 0 {
+				continue
+			}
+			want = layout.versionShift()
+		case "time":
+			want = layout.timeShift()
+		case "node":
+			want = layout.nodeShift()
+		case "sequence":
+			want = layout.sequenceShift()
+		default:
+			want, _, _ = layout.field(f.Name)
+		}
+		if f.Offset != want {
+			return fmt.Errorf("%w: version %d field %q has offset %d, want %d",
+				ErrInvalidLayout, layout.Version, f.Name, f.Offset, want)
+		}
+	}
+
+	*l = layout
+	return nil
+}
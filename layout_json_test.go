@@ -0,0 +1,89 @@
+package snowflake
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestVersionLayout_MarshalJSON(t *testing.T) {
+	layout, ok := LookupLayout(Version0)
+	if !ok {
+		t.Fatal("Version0 layout not registered")
+	}
+
+	data, err := json.Marshal(layout)
+	if err != nil {
+		t.Fatalf("Failed to marshal layout: %v", err)
+	}
+
+	var spec layoutJSON
+	if err := json.Unmarshal(data, &spec); err != nil {
+		t.Fatalf("Failed to unmarshal spec: %v", err)
+	}
+
+	if spec.Version != Version0 || spec.TimeUnit != "1ms" || spec.TimeUnitNS != 1_000_000 {
+		t.Errorf("Unexpected spec header: %s", data)
+	}
+	if !spec.Epoch.Equal(layout.Epoch) {
+		t.Errorf("Expected epoch %s, got %s", layout.Epoch, spec.Epoch)
+	}
+
+	want := []layoutFieldJSON{
+		{Name: "version", Bits: 3, Offset: 61},
+		{Name: "time", Bits: 45, Offset: 16},
+		{Name: "node", Bits: 8, Offset: 8},
+		{Name: "sequence", Bits: 8, Offset: 0},
+	}
+	if len(spec.Fields) != len(want) {
+		t.Fatalf("Expected %d fields, got %d", len(want), len(spec.Fields))
+	}
+	for i, f := range spec.Fields {
+		if f != want[i] {
+			t.Errorf("Field %d: expected %+v, got %+v", i, want[i], f)
+		}
+	}
+}
+
+func TestVersionLayout_UnmarshalJSON(t *testing.T) {
+	layouts := []*VersionLayout{TwitterLayout(), SonyflakeLayout(), DiscordLayout(), InstagramLayout()}
+	for _, v := range []Version{Version0, Version2, Version3} {
+		layout, _ := LookupLayout(v)
+		layouts = append(layouts, &layout)
+	}
+
+	for _, layout := range layouts {
+		data, err := json.Marshal(layout)
+		if err != nil {
+			t.Fatalf("Failed to marshal layout: %v", err)
+		}
+		var got VersionLayout
+		if err := json.Unmarshal(data, &got); err != nil {
+			t.Fatalf("Failed to unmarshal %s: %v", data, err)
+		}
+		if !reflect.DeepEqual(&got, layout) {
+			t.Errorf("Round trip mismatch:\n got %+v\nwant %+v", got, *layout)
+		}
+	}
+}
+
+func TestVersionLayout_UnmarshalJSON_Invalid(t *testing.T) {
+	tests := map[string]string{
+		"wrong offset": `{"version":0,"epoch":"2026-01-01T00:00:00Z","time_unit_ns":1000000,"fields":[
+			{"name":"version","bits":3,"offset":61},{"name":"time","bits":45,"offset":16},
+			{"name":"node","bits":8,"offset":0},{"name":"sequence","bits":8,"offset":8}]}`,
+		"short of 64 bits": `{"version":0,"epoch":"2026-01-01T00:00:00Z","time_unit":"1ms","fields":[
+			{"name":"version","bits":3,"offset":58},{"name":"time","bits":45,"offset":13},
+			{"name":"node","bits":5,"offset":8},{"name":"sequence","bits":8,"offset":0}]}`,
+		"no time unit": `{"version":0,"epoch":"2026-01-01T00:00:00Z","fields":[
+			{"name":"version","bits":3,"offset":61},{"name":"time","bits":45,"offset":16},
+			{"name":"node","bits":8,"offset":8},{"name":"sequence","bits":8,"offset":0}]}`,
+	}
+	for name, spec := range tests {
+		var layout VersionLayout
+		if err := json.Unmarshal([]byte(spec), &layout); !errors.Is(err, ErrInvalidLayout) {
+			t.Errorf("%s: expected ErrInvalidLayout, got %v", name, err)
+		}
+	}
+}
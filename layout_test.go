@@ -0,0 +1,179 @@
+package snowflake
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestVersionBits_CustomWidths(t *testing.T) {
+	layouts := []*VersionLayout{
+		{
+			Version:      17,
+			VersionBits:  5,
+			TimeBits:     43,
+			NodeBits:     8,
+			SequenceBits: 8,
+			TimeUnit:     time.Millisecond,
+			Epoch:        EpochY2026,
+			MaxNodeID:    (1 << 8) - 1,
+			MaxSequence:  (1 << 8) - 1,
+			MaxTimestamp: (1 << 43) - 1,
+		},
+		{
+			Version:      12,
+			VersionBits:  4,
+			TimeBits:     44,
+			NodeBits:     8,
+			SequenceBits: 8,
+			TimeUnit:     time.Millisecond,
+			Epoch:        EpochY2026,
+			MaxNodeID:    (1 << 8) - 1,
+			MaxSequence:  (1 << 8) - 1,
+			MaxTimestamp: (1 << 44) - 1,
+		},
+	}
+
+	for _, layout := range layouts {
+		withTestLayout(t, layout)
+	}
+
+	for _, layout := range layouts {
+		gen, err := NewGenerator(Config{Version: layout.Version, NodeID: 9})
+		if err != nil {
+			t.Fatalf("Failed to create version %d generator: %v", layout.Version, err)
+		}
+
+		id, err := gen.NextID()
+		if err != nil {
+			t.Fatalf("Failed to generate ID: %v", err)
+		}
+
+		if got := id >> (64 - layout.VersionBits); got != uint64(layout.Version) {
+			t.Errorf("Expected version %d in top %d bits, got %d", layout.Version, layout.VersionBits, got)
+		}
+
+		decoded, err := Decode(id)
+		if err != nil {
+			t.Fatalf("Failed to decode version %d ID: %v", layout.Version, err)
+		}
+		if decoded.Version != layout.Version || decoded.NodeID != 9 {
+			t.Errorf("Unexpected decode for version %d: %s", layout.Version, decoded)
+		}
+		if diff := time.Since(decoded.Time); diff < 0 || diff > time.Second {
+			t.Errorf("Decoded time seems incorrect: %v (diff: %v)", decoded.Time, diff)
+		}
+	}
+}
+
+func TestVersionLayout_Validate(t *testing.T) {
+	valid := *versionLayouts[Version0]
+
+	tests := []struct {
+		name   string
+		modify func(l *VersionLayout)
+	}{
+		{name: "bits do not sum to 64", modify: func(l *VersionLayout) { l.SequenceBits = 7 }},
+		{name: "version too wide", modify: func(l *VersionLayout) { l.Version = 8 }},
+		{name: "no time unit", modify: func(l *VersionLayout) { l.TimeUnit = 0 }},
+		{name: "max node mismatch", modify: func(l *VersionLayout) { l.MaxNodeID = 1023 }},
+	}
+
+	if err := valid.validate(); err != nil {
+		t.Fatalf("Version0 layout failed validation: %v", err)
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			layout := valid
+			tt.modify(&layout)
+			if err := layout.validate(); !errors.Is(err, ErrInvalidLayout) {
+				t.Errorf("Expected ErrInvalidLayout, got %v", err)
+			}
+		})
+	}
+}
+
+func TestVersionlessLayout(t *testing.T) {
+	versionless := &VersionLayout{
+		Version:      7,
+		VersionBits:  0,
+		TimeBits:     42,
+		NodeBits:     10,
+		SequenceBits: 12,
+		TimeUnit:     time.Millisecond,
+		Epoch:        EpochY2020,
+		MaxNodeID:    (1 << 10) - 1,
+		MaxSequence:  (1 << 12) - 1,
+		MaxTimestamp: (1 << 42) - 1,
+	}
+
+	gen, err := NewGenerator(Config{Layout: versionless, NodeID: 513})
+	if err != nil {
+		t.Fatalf("Failed to create versionless generator: %v", err)
+	}
+
+	id, err := gen.NextID()
+	if err != nil {
+		t.Fatalf("Failed to generate ID: %v", err)
+	}
+
+	decoded, err := DecodeWithLayout(id, versionless)
+	if err != nil {
+		t.Fatalf("Failed to decode versionless ID: %v", err)
+	}
+	if decoded.NodeID != 513 {
+		t.Errorf("Expected node ID 513, got %d", decoded.NodeID)
+	}
+	if diff := time.Since(decoded.Time); diff < 0 || diff > time.Second {
+		t.Errorf("Decoded time seems incorrect: %v (diff: %v)", decoded.Time, diff)
+	}
+
+	// The top bits hold time, not a version number
+	if got := id >> 54; got != decoded.Timestamp>>32 {
+		t.Errorf("Expected time in the top bits, got %d", got)
+	}
+}
+
+func TestDecodeWithLayout_VersionMismatch(t *testing.T) {
+	layout, _ := LookupLayout(Version0)
+	if _, err := DecodeWithLayout(uint64(5)<<61, &layout); !errors.Is(err, ErrInvalidVersion) {
+		t.Errorf("Expected ErrInvalidVersion, got %v", err)
+	}
+}
+
+func TestVersionLayout_Capacity(t *testing.T) {
+	tests := []struct {
+		version   Version
+		perSecond float64
+		nodes     uint64
+		exhausted time.Time
+	}{
+		{Version0, 256_000, 256, time.UnixMilli(EpochY2026.UnixMilli() + 1<<45).UTC()},
+		{Version2, 16_000_000, 128, EpochY2026.Add(time.Duration(1<<50) * time.Microsecond)},
+		{Version3, 8192, 65536, EpochY2026.Add(time.Duration(1<<32) * time.Second)},
+	}
+	for _, tt := range tests {
+		layout, _ := LookupLayout(tt.version)
+		if got := layout.MaxIDsPerSecond(); got != tt.perSecond {
+			t.Errorf("Version %d: MaxIDsPerSecond = %v, want %v", tt.version, got, tt.perSecond)
+		}
+		if got := layout.MaxNodes(); got != tt.nodes {
+			t.Errorf("Version %d: MaxNodes = %d, want %d", tt.version, got, tt.nodes)
+		}
+		if got := layout.ExhaustionTime(); !got.Equal(tt.exhausted) {
+			t.Errorf("Version %d: ExhaustionTime = %s, want %s", tt.version, got, tt.exhausted)
+		}
+	}
+
+	twitter := TwitterLayout()
+	if got := twitter.MaxIDsPerSecond(); got != 4_096_000 {
+		t.Errorf("Twitter: MaxIDsPerSecond = %v, want 4096000", got)
+	}
+	if got, want := twitter.ExhaustionTime(), time.UnixMilli(1288834974657+1<<41).UTC(); !got.Equal(want) {
+		t.Errorf("Twitter: ExhaustionTime = %s, want %s", got, want)
+	}
+	if got := SonyflakeLayout().MaxIDsPerSecond(); got != 25_600 {
+		t.Errorf("Sonyflake: MaxIDsPerSecond = %v, want 25600", got)
+	}
+}
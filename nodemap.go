@@ -0,0 +1,71 @@
+package snowflake
+
+import (
+	"fmt"
+	"hash/fnv"
+	"sort"
+)
+
+// AssignNodeIDs deterministically maps hosts to node IDs in [0, maxNodeID].
+// Each host prefers the slot given by a hash of its name; collisions probe
+// upward to the next free slot, with hosts resolved in sorted order so the
+// result does not depend on inventory order.
+//
+// Assignments in previous are kept for hosts still present, so feeding the
+// last mapping back in keeps existing hosts stable as the inventory grows.
+// Entries for hosts no longer listed are released.
+func AssignNodeIDs(hosts []string, maxNodeID uint64, previous map[string]uint64) (map[string]uint64, error) {
+	unique := make(map[string]bool, len(hosts))
+	for _, host := range hosts {
+		unique[host] = true
+	}
+	if slots := maxNodeID + 1; slots != 0 && uint64(len(unique)) > slots {
+		return nil, fmt.Errorf("%w: %d hosts exceed %d node IDs", ErrInvalidNodeID, len(unique), slots)
+	}
+
+	assigned := make(map[string]uint64, len(unique))
+	taken := make(map[uint64]string, len(unique))
+
+	var pending []string
+	for host := range unique {
+		id, ok := previous[host]
+		if !ok {
+			pending = append(pending, host)
+			continue
+		}
+		if id > maxNodeID {
+			return nil, fmt.Errorf("%w: %q pinned to %d (max: %d)", ErrInvalidNodeID, host, id, maxNodeID)
+		}
+		if other, dup := taken[id]; dup {
+			return nil, fmt.Errorf("%w: %q and %q both pinned to %d", ErrInvalidNodeID, host, other, id)
+		}
+		assigned[host] = id
+		taken[id] = host
+	}
+
+	sort.Strings(pending)
+	for _, host := range pending {
+		h := fnv.New64a()
+		h.Write([]byte(host))
+
+		id := h.Sum64()
+		if maxNodeID != ^uint64(0) {
+			id %= maxNodeID + 1
+		}
+		for {
+			if _, used := taken[id]; !used {
+				break
+			}
+			if id == maxNodeID {
+				id = 0
+			} else {
+				id++
+			}
+		}
+
+		assigned[host] = id
+		taken[id] = host
+	}
+
+	return assigned, nil
+}
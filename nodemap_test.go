@@ -0,0 +1,67 @@
+package snowflake
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestAssignNodeIDs(t *testing.T) {
+	hosts := make([]string, 200)
+	for i := range hosts {
+		hosts[i] = fmt.Sprintf("pod-%d", i)
+	}
+
+	first, err := AssignNodeIDs(hosts, 255, nil)
+	if err != nil {
+		t.Fatalf("Failed to assign node IDs: %v", err)
+	}
+	if len(first) != len(hosts) {
+		t.Fatalf("Expected %d assignments, got %d", len(hosts), len(first))
+	}
+	seen := make(map[uint64]string)
+	for host, id := range first {
+		if id > 255 {
+			t.Errorf("%s: node ID %d out of range", host, id)
+		}
+		if other, dup := seen[id]; dup {
+			t.Errorf("%s and %s share node ID %d", host, other, id)
+		}
+		seen[id] = host
+	}
+
+	// Inventory order must not matter
+	reversed := make([]string, len(hosts))
+	for i, host := range hosts {
+		reversed[len(hosts)-1-i] = host
+	}
+	again, _ := AssignNodeIDs(reversed, 255, nil)
+	for host, id := range first {
+		if again[host] != id {
+			t.Errorf("%s: expected %d regardless of order, got %d", host, id, again[host])
+		}
+	}
+
+	// Growing the inventory keeps previous assignments
+	grown, err := AssignNodeIDs(append(hosts, "pod-new-a", "pod-new-b"), 255, first)
+	if err != nil {
+		t.Fatalf("Failed to assign grown inventory: %v", err)
+	}
+	for host, id := range first {
+		if grown[host] != id {
+			t.Errorf("%s: expected pinned %d, got %d", host, id, grown[host])
+		}
+	}
+}
+
+func TestAssignNodeIDs_Errors(t *testing.T) {
+	if _, err := AssignNodeIDs([]string{"a", "b", "c"}, 1, nil); !errors.Is(err, ErrInvalidNodeID) {
+		t.Errorf("Expected ErrInvalidNodeID for too many hosts, got %v", err)
+	}
+	if _, err := AssignNodeIDs([]string{"a", "b"}, 7, map[string]uint64{"a": 3, "b": 3}); !errors.Is(err, ErrInvalidNodeID) {
+		t.Errorf("Expected ErrInvalidNodeID for conflicting pins, got %v", err)
+	}
+	if _, err := AssignNodeIDs([]string{"a"}, 7, map[string]uint64{"a": 8}); !errors.Is(err, ErrInvalidNodeID) {
+		t.Errorf("Expected ErrInvalidNodeID for out-of-range pin, got %v", err)
+	}
+}
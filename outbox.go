@@ -0,0 +1,158 @@
+package snowflake
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// Outbox implements the transactional outbox pattern: a row and the event
+// announcing it are written in one transaction under the same snowflake ID,
+// and a publisher later drains the events in ID order. The outbox table
+// must have this shape (types adjusted to the database):
+//
+//	CREATE TABLE outbox (
+//		id      BIGINT PRIMARY KEY,
+//		topic   TEXT NOT NULL,
+//		payload BLOB
+//	)
+//
+// IDs are stored as their int64 bit pattern, which preserves order for
+// every builtin version. Run a single publisher per table; events are
+// delivered at least once.
+type Outbox struct {
+	gen         *Generator
+	table       string
+	placeholder func(i int) string
+	onError     func(error)
+}
+
+// OutboxEvent is an event read from the outbox
+type OutboxEvent struct {
+	ID      uint64
+	Topic   string
+	Payload []byte
+}
+
+// OutboxConfig configures an Outbox
+type OutboxConfig struct {
+	// Table is the outbox table name (default: "outbox")
+	Table string
+
+	// Placeholder returns the bind parameter for the i-th (zero-based)
+	// argument of a statement (default: "?"; use DollarPlaceholder for
+	// Postgres)
+	Placeholder func(i int) string
+
+	// OnError, if set, receives the poll errors Run retries past
+	OnError func(error)
+}
+
+// DollarPlaceholder numbers bind parameters as $1, $2, ...
+func DollarPlaceholder(i int) string {
+	return fmt.Sprintf("$%d", i+1)
+}
+
+// NewOutbox returns an outbox minting IDs from gen
+func NewOutbox(gen *Generator, cfg OutboxConfig) *Outbox {
+	o := &Outbox{gen: gen, table: cfg.Table, placeholder: cfg.Placeholder, onError: cfg.OnError}
+	if o.table == "" {
+		o.table = "outbox"
+	}
+	if o.placeholder == nil {
+		o.placeholder = func(int) string { return "?" }
+	}
+	return o
+}
+
+// Write mints an ID, calls insert to write the row keyed by it within tx,
+// and enqueues an event with the same ID. The caller commits tx.
+func (o *Outbox) Write(ctx context.Context, tx *sql.Tx, topic string, payload []byte, insert func(id uint64) error) (uint64, error) {
+	id, err := o.gen.NextIDContext(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := insert(id); err != nil {
+		return 0, err
+	}
+
+	query := fmt.Sprintf("INSERT INTO %s (id, topic, payload) VALUES (%s, %s, %s)",
+		o.table, o.placeholder(0), o.placeholder(1), o.placeholder(2))
+	if _, err := tx.ExecContext(ctx, query, int64(id), topic, payload); err != nil {
+		return 0, fmt.Errorf("enqueue event %d: %w", id, err)
+	}
+
+	return id, nil
+}
+
+// Poll publishes up to limit pending events in ID order, deleting each one
+// once publish succeeds. It stops at the first publish error, leaving that
+// event and the ones after it for the next poll, and returns the number of
+// events published.
+func (o *Outbox) Poll(ctx context.Context, db *sql.DB, limit int, publish func(context.Context, OutboxEvent) error) (int, error) {
+	if limit < 1 {
+		return 0, fmt.Errorf("invalid poll limit: %d", limit)
+	}
+
+	query := fmt.Sprintf("SELECT id, topic, payload FROM %s ORDER BY id LIMIT %d", o.table, limit)
+	rows, err := db.QueryContext(ctx, query)
+	if err != nil {
+		return 0, err
+	}
+
+	var events []OutboxEvent
+	for rows.Next() {
+		var id int64
+		var event OutboxEvent
+		if err := rows.Scan(&id, &event.Topic, &event.Payload); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		event.ID = uint64(id)
+		events = append(events, event)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	remove := fmt.Sprintf("DELETE FROM %s WHERE id = %s", o.table, o.placeholder(0))
+	for i, event := range events {
+		if err := publish(ctx, event); err != nil {
+			return i, fmt.Errorf("publish event %d: %w", event.ID, err)
+		}
+		if _, err := db.ExecContext(ctx, remove, int64(event.ID)); err != nil {
+			return i, fmt.Errorf("delete event %d: %w", event.ID, err)
+		}
+	}
+
+	return len(events), nil
+}
+
+// Run polls every interval until ctx is done, returning ctx's error. Poll
+// errors are reported to OutboxConfig.OnError and retried on the next tick.
+func (o *Outbox) Run(ctx context.Context, db *sql.DB, interval time.Duration, limit int, publish func(context.Context, OutboxEvent) error) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		// Drain backlogs without waiting for the next tick
+		for {
+			n, err := o.Poll(ctx, db, limit, publish)
+			if err != nil && ctx.Err() == nil && o.onError != nil {
+				o.onError(err)
+			}
+			if err != nil || n < limit {
+				break
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,169 @@
+package snowflake
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// outboxDriver is an in-memory database/sql driver understanding just the
+// statements Outbox issues
+type outboxDriver struct {
+	mu     sync.Mutex
+	events map[int64]OutboxEvent
+}
+
+func (d *outboxDriver) Open(string) (driver.Conn, error) { return outboxConn{d}, nil }
+
+type outboxConn struct{ d *outboxDriver }
+
+func (c outboxConn) Prepare(query string) (driver.Stmt, error) {
+	return outboxStmt{d: c.d, query: query}, nil
+}
+func (c outboxConn) Close() error              { return nil }
+func (c outboxConn) Begin() (driver.Tx, error) { return c, nil }
+func (c outboxConn) Commit() error             { return nil }
+func (c outboxConn) Rollback() error           { return nil }
+
+type outboxStmt struct {
+	d     *outboxDriver
+	query string
+}
+
+func (s outboxStmt) Close() error  { return nil }
+func (s outboxStmt) NumInput() int { return -1 }
+
+func (s outboxStmt) Exec(args []driver.Value) (driver.Result, error) {
+	s.d.mu.Lock()
+	defer s.d.mu.Unlock()
+
+	id := args[0].(int64)
+	switch {
+	case strings.HasPrefix(s.query, "INSERT INTO outbox "):
+		s.d.events[id] = OutboxEvent{ID: uint64(id), Topic: args[1].(string), Payload: args[2].([]byte)}
+	case strings.HasPrefix(s.query, "DELETE FROM outbox "):
+		delete(s.d.events, id)
+	default:
+		return nil, fmt.Errorf("unexpected statement %q", s.query)
+	}
+	return driver.RowsAffected(1), nil
+}
+
+func (s outboxStmt) Query([]driver.Value) (driver.Rows, error) {
+	var limit int
+	if _, err := fmt.Sscanf(s.query, "SELECT id, topic, payload FROM outbox ORDER BY id LIMIT %d", &limit); err != nil {
+		return nil, fmt.Errorf("unexpected query %q", s.query)
+	}
+
+	s.d.mu.Lock()
+	defer s.d.mu.Unlock()
+
+	rows := &outboxRows{}
+	for _, event := range s.d.events {
+		rows.events = append(rows.events, event)
+	}
+	sort.Slice(rows.events, func(i, j int) bool { return rows.events[i].ID < rows.events[j].ID })
+	if len(rows.events) > limit {
+		rows.events = rows.events[:limit]
+	}
+	return rows, nil
+}
+
+type outboxRows struct{ events []OutboxEvent }
+
+func (r *outboxRows) Columns() []string { return []string{"id", "topic", "payload"} }
+func (r *outboxRows) Close() error      { return nil }
+func (r *outboxRows) Next(dest []driver.Value) error {
+	if len(r.events) == 0 {
+		return io.EOF
+	}
+	event := r.events[0]
+	r.events = r.events[1:]
+	dest[0], dest[1], dest[2] = int64(event.ID), event.Topic, event.Payload
+	return nil
+}
+
+func TestOutbox(t *testing.T) {
+	d := &outboxDriver{events: make(map[int64]OutboxEvent)}
+	sql.Register("snowflake-outbox", d)
+	db, err := sql.Open("snowflake-outbox", "")
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	defer db.Close()
+
+	gen, _ := NewGenerator(Config{Version: Version0})
+	outbox := NewOutbox(gen, OutboxConfig{})
+	ctx := context.Background()
+
+	var rowIDs []uint64
+	for i := 0; i < 5; i++ {
+		tx, err := db.Begin()
+		if err != nil {
+			t.Fatalf("Failed to begin: %v", err)
+		}
+		id, err := outbox.Write(ctx, tx, "orders", []byte{byte(i)}, func(id uint64) error {
+			rowIDs = append(rowIDs, id)
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("Failed to write: %v", err)
+		}
+		if id != rowIDs[i] {
+			t.Errorf("Expected event ID %d to match row ID %d", id, rowIDs[i])
+		}
+		if err := tx.Commit(); err != nil {
+			t.Fatalf("Failed to commit: %v", err)
+		}
+	}
+
+	// A failing publisher keeps the failed event and everything after it
+	var published []uint64
+	errBroker := errors.New("broker down")
+	n, err := outbox.Poll(ctx, db, 10, func(_ context.Context, e OutboxEvent) error {
+		if len(published) == 2 {
+			return errBroker
+		}
+		published = append(published, e.ID)
+		return nil
+	})
+	if n != 2 || !errors.Is(err, errBroker) {
+		t.Fatalf("Expected 2 events and broker error, got %d, %v", n, err)
+	}
+
+	n, err = outbox.Poll(ctx, db, 10, func(_ context.Context, e OutboxEvent) error {
+		if e.Topic != "orders" {
+			t.Errorf("Unexpected topic %q", e.Topic)
+		}
+		published = append(published, e.ID)
+		return nil
+	})
+	if n != 3 || err != nil {
+		t.Fatalf("Expected 3 events, got %d, %v", n, err)
+	}
+
+	if len(published) != len(rowIDs) {
+		t.Fatalf("Expected %d published events, got %d", len(rowIDs), len(published))
+	}
+	for i := range rowIDs {
+		if published[i] != rowIDs[i] {
+			t.Errorf("Event %d: expected ID %d, got %d", i, rowIDs[i], published[i])
+		}
+	}
+	if len(d.events) != 0 {
+		t.Errorf("Expected empty outbox, %d events left", len(d.events))
+	}
+}
+
+func TestDollarPlaceholder(t *testing.T) {
+	if got := DollarPlaceholder(0); got != "$1" {
+		t.Errorf("Expected $1, got %s", got)
+	}
+}
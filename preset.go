@@ -0,0 +1,118 @@
+package snowflake
+
+import "time"
+
+// TwitterLayout returns the layout of classic Twitter snowflake IDs, as
+// also issued by bwmarrin/snowflake with its default settings: versionless,
+// in milliseconds since 2010-11-04T01:42:54.657Z:
+//
+//	[1 bit zero][41 bits time][10 bits worker][12 bits sequence]
+//
+// The worker ID is the node field. The top bits of Twitter IDs are zero,
+// which Decode reads as Version0, so decode them with DecodeWithLayout.
+func TwitterLayout() *VersionLayout {
+	return &VersionLayout{
+		Name:         "twitter",
+		TimeBits:     41,
+		NodeBits:     10,
+		SequenceBits: 12,
+		TimeUnit:     time.Millisecond,
+		Epoch:        EpochTwitter2010,
+		MaxNodeID:    (1 << 10) - 1, // 1023
+		MaxSequence:  (1 << 12) - 1, // 4095
+		MaxTimestamp: (1 << 41) - 1, // ~69 years, until 2080
+	}
+}
+
+// SonyflakeLayout returns the layout of sony/sonyflake IDs: versionless,
+// in 10ms units since 2014-09-01, with the machine ID below the sequence:
+//
+//	[1 bit zero][39 bits time][8 bits sequence][16 bits machine]
+//
+// Use it as Config.Layout to keep issuing IDs in an existing Sonyflake ID
+// space (the machine ID is Config.NodeID), and with DecodeWithLayout to
+// decode them. Its Version is only a local label.
+func SonyflakeLayout() *VersionLayout {
+	return &VersionLayout{
+		Name:         "sonyflake",
+		TimeBits:     39,
+		NodeBits:     16,
+		SequenceBits: 8,
+		TimeUnit:     10 * time.Millisecond,
+		Epoch:        EpochSonyflake2014,
+		MaxNodeID:    (1 << 16) - 1, // 65535
+		MaxSequence:  (1 << 8) - 1,  // 255
+		MaxTimestamp: (1 << 39) - 1, // ~174 years, until 2188
+		NodeLast:     true,
+	}
+}
+
+// InstagramLayout returns the layout of Instagram's published ID scheme:
+// versionless, in milliseconds since 2011-08-24T21:07:01.721Z, with a
+// logical shard ID below the time and a per-shard sequence below that:
+//
+//	[41 bits time][13 bits shard][10 bits sequence]
+//
+// The shard is a ShardField; issue IDs with a ShardedGenerator so each
+// shard has its own sequence, as Instagram's per-shard database sequences
+// do. The time field reaches the top bit in 2046, after which IDs no
+// longer fit a signed int64.
+func InstagramLayout() *VersionLayout {
+	return &VersionLayout{
+		Name:         "instagram",
+		TimeBits:     41,
+		SequenceBits: 10,
+		TimeUnit:     time.Millisecond,
+		Epoch:        EpochInstagram2011,
+		MaxSequence:  (1 << 10) - 1, // 1023
+		MaxTimestamp: (1 << 41) - 1, // ~69 years, until 2081
+		Fields:       []Field{{Name: ShardField, Bits: 13}},
+	}
+}
+
+// DiscordWorkerField is the DiscordLayout field holding Discord's internal
+// worker ID. The node field holds the internal process ID.
+const DiscordWorkerField = "worker"
+
+// DiscordLayout returns the layout of Discord snowflakes: versionless, in
+// milliseconds since 2015-01-01, with worker and process IDs above a
+// per-process increment:
+//
+//	[42 bits time][5 bits worker][5 bits process][12 bits increment]
+//
+// The worker ID decodes into Fields[DiscordWorkerField], the process ID
+// into NodeID and the increment into Sequence.
+func DiscordLayout() *VersionLayout {
+	return &VersionLayout{
+		Name:         "discord",
+		TimeBits:     42,
+		NodeBits:     5,
+		SequenceBits: 12,
+		TimeUnit:     time.Millisecond,
+		Epoch:        EpochDiscord2015,
+		MaxNodeID:    (1 << 5) - 1,  // 31
+		MaxSequence:  (1 << 12) - 1, // 4095
+		MaxTimestamp: (1 << 42) - 1, // ~139 years, until 2154
+		Fields:       []Field{{Name: DiscordWorkerField, Bits: 5}},
+	}
+}
+
+// MastodonLayout returns the layout of Mastodon status IDs: milliseconds
+// since the Unix epoch above 16 low bits that Mastodon fills from a hash
+// and a database sequence:
+//
+//	[48 bits time][16 bits sequence]
+//
+// The low bits decode into Sequence; they are not a counter, so only the
+// time is meaningful across IDs. There is no node field.
+func MastodonLayout() *VersionLayout {
+	return &VersionLayout{
+		Name:         "mastodon",
+		TimeBits:     48,
+		SequenceBits: 16,
+		TimeUnit:     time.Millisecond,
+		Epoch:        EpochUnix,
+		MaxSequence:  (1 << 16) - 1, // 65535
+		MaxTimestamp: (1 << 48) - 1, // ~8900 years
+	}
+}
@@ -0,0 +1,108 @@
+package snowflake
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSonyflakeLayout_Decode(t *testing.T) {
+	// Sonyflake ID for machine 0x1234, sequence 3, at 2024-01-01T00:00:00Z
+	decoded, err := DecodeWithLayout(494152093532361268, SonyflakeLayout())
+	if err != nil {
+		t.Fatalf("DecodeWithLayout failed: %v", err)
+	}
+
+	want := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	if !decoded.Time.Equal(want) {
+		t.Errorf("Expected time %s, got %s", want, decoded.Time)
+	}
+	if decoded.NodeID != 0x1234 || decoded.Sequence != 3 {
+		t.Errorf("Expected machine 0x1234 and sequence 3, got %s", decoded)
+	}
+}
+
+func TestSonyflakeLayout_Generate(t *testing.T) {
+	layout := SonyflakeLayout()
+	gen, err := NewGenerator(Config{Layout: layout, NodeID: 0xBEEF})
+	if err != nil {
+		t.Fatalf("Failed to create generator: %v", err)
+	}
+
+	ids, err := gen.NextIDs(600)
+	if err != nil {
+		t.Fatalf("Failed to generate IDs: %v", err)
+	}
+
+	for i, id := range ids {
+		if id>>63 != 0 {
+			t.Fatalf("Expected top bit to be zero in %d", id)
+		}
+		if id&0xFFFF != 0xBEEF {
+			t.Fatalf("Expected machine ID in the low 16 bits of %d", id)
+		}
+		if i > 0 && id <= ids[i-1] {
+			t.Fatalf("IDs not increasing: %d then %d", ids[i-1], id)
+		}
+	}
+
+	decoded, err := DecodeWithLayout(ids[0], layout)
+	if err != nil {
+		t.Fatalf("DecodeWithLayout failed: %v", err)
+	}
+	if decoded.NodeID != 0xBEEF {
+		t.Errorf("Expected machine 0xBEEF, got %d", decoded.NodeID)
+	}
+	if diff := time.Since(decoded.Time); diff < 0 || diff > time.Second {
+		t.Errorf("Decoded time seems incorrect: %v (diff: %v)", decoded.Time, diff)
+	}
+}
+
+func TestInstagramLayout_Decode(t *testing.T) {
+	// Components from Instagram's published example: 1387263000ms past
+	// the epoch, shard 1341 (user 31341 mod 2000), sequence 5001 mod 1024
+	id := uint64(1387263000)<<23 | 1341<<10 | 905
+	decoded, err := DecodeWithLayout(id, InstagramLayout())
+	if err != nil {
+		t.Fatalf("DecodeWithLayout failed: %v", err)
+	}
+
+	shard, ok := decoded.Shard()
+	if decoded.Timestamp != 1387263000 || !ok || shard != 1341 || decoded.Sequence != 905 {
+		t.Errorf("Unexpected decode: %s (shard %d)", decoded, shard)
+	}
+	if want := EpochInstagram2011.Add(1387263000 * time.Millisecond); !decoded.Time.Equal(want) {
+		t.Errorf("Expected time %s, got %s", want, decoded.Time)
+	}
+}
+
+func TestDiscordLayout_Decode(t *testing.T) {
+	// Example from Discord's API reference
+	decoded, err := DecodeWithLayout(175928847299117063, DiscordLayout())
+	if err != nil {
+		t.Fatalf("DecodeWithLayout failed: %v", err)
+	}
+
+	want := time.Date(2016, 4, 30, 11, 18, 25, 796e6, time.UTC)
+	if !decoded.Time.Equal(want) {
+		t.Errorf("Expected time %s, got %s", want, decoded.Time)
+	}
+	if decoded.Fields[DiscordWorkerField] != 1 || decoded.NodeID != 0 || decoded.Sequence != 7 {
+		t.Errorf("Expected worker 1, process 0, increment 7, got %s", decoded)
+	}
+}
+
+func TestMastodonLayout_Decode(t *testing.T) {
+	want := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	id := uint64(want.UnixMilli())<<16 | 0xA1B2
+
+	decoded, err := DecodeWithLayout(id, MastodonLayout())
+	if err != nil {
+		t.Fatalf("DecodeWithLayout failed: %v", err)
+	}
+	if !decoded.Time.Equal(want) {
+		t.Errorf("Expected time %s, got %s", want, decoded.Time)
+	}
+	if decoded.Sequence != 0xA1B2 {
+		t.Errorf("Expected low bits 0xA1B2, got %#x", decoded.Sequence)
+	}
+}
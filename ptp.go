@@ -0,0 +1,47 @@
+package snowflake
+
+import (
+	"os"
+	"time"
+)
+
+// PTPClock reads a PTP hardware clock (PHC) such as /dev/ptp0, giving
+// nodes disciplined by the same PTP grandmaster far tighter agreement than
+// NTP-synced system clocks. It is supported on Linux only.
+type PTPClock struct {
+	// UTCOffset is subtracted from PHC readings. PHCs commonly run on TAI;
+	// set this to the current TAI-UTC offset (37s) in that case. Set it
+	// before the clock is in use.
+	UTCOffset time.Duration
+
+	device   *os.File
+	clockID  int
+	fallback Clock
+}
+
+// NewPTPClock opens a PTP hardware clock device. If a later read fails,
+// Now falls back to fallback (e.g. SystemClock) when it is non-nil.
+func NewPTPClock(device string, fallback Clock) (*PTPClock, error) {
+	c := &PTPClock{fallback: fallback}
+	if err := c.open(device); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// Now implements Clock
+func (c *PTPClock) Now() (time.Time, error) {
+	t, err := c.read()
+	if err != nil {
+		if c.fallback != nil {
+			return c.fallback.Now()
+		}
+		return time.Time{}, err
+	}
+	return t.Add(-c.UTCOffset), nil
+}
+
+// Close releases the clock device
+func (c *PTPClock) Close() error {
+	return c.device.Close()
+}
@@ -0,0 +1,47 @@
+//go:build linux
+
+package snowflake
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+	"syscall"
+	"time"
+	"unsafe"
+)
+
+// clockFD marks a dynamic POSIX clock ID referring to an open device
+const clockFD = 3
+
+// fdToClockID returns the dynamic clock ID for an open PHC device, as the
+// kernel's FD_TO_CLOCKID macro does
+func fdToClockID(fd uintptr) int {
+	return int(int32(^uint32(fd)<<3 | clockFD))
+}
+
+func (c *PTPClock) open(device string) error {
+	f, err := os.Open(device)
+	if err != nil {
+		return err
+	}
+	c.device = f
+	c.clockID = fdToClockID(f.Fd())
+
+	if _, err := c.read(); err != nil {
+		f.Close()
+		return fmt.Errorf("%s: %w", device, err)
+	}
+	return nil
+}
+
+func (c *PTPClock) read() (time.Time, error) {
+	var ts syscall.Timespec
+	_, _, errno := syscall.Syscall(syscall.SYS_CLOCK_GETTIME,
+		uintptr(c.clockID), uintptr(unsafe.Pointer(&ts)), 0)
+	runtime.KeepAlive(c.device)
+	if errno != 0 {
+		return time.Time{}, fmt.Errorf("clock_gettime: %w", errno)
+	}
+	return time.Unix(ts.Unix()), nil
+}
@@ -0,0 +1,41 @@
+//go:build linux
+
+package snowflake
+
+import (
+	"os"
+	"testing"
+	"time"
+)
+
+func TestFdToClockID(t *testing.T) {
+	// FD_TO_CLOCKID(3) == ((~3) << 3) | 3
+	if got := fdToClockID(3); got != -29 {
+		t.Errorf("Expected -29, got %d", got)
+	}
+}
+
+func TestPTPClock(t *testing.T) {
+	if _, err := NewPTPClock("/dev/nonexistent-ptp", SystemClock); !os.IsNotExist(err) {
+		t.Errorf("Expected not-exist error, got %v", err)
+	}
+
+	// A regular file is not a clock: opening succeeds but reads fail
+	if _, err := NewPTPClock(os.DevNull, SystemClock); err == nil {
+		t.Error("Expected error for non-clock device")
+	}
+
+	c, err := NewPTPClock("/dev/ptp0", nil)
+	if err != nil {
+		t.Skipf("No PTP hardware clock: %v", err)
+	}
+	defer c.Close()
+
+	now, err := c.Now()
+	if err != nil {
+		t.Fatalf("Failed to read PHC: %v", err)
+	}
+	if d := time.Since(now); d > time.Minute || d < -time.Minute {
+		t.Errorf("PHC reading %s far from system time", now)
+	}
+}
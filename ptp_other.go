@@ -0,0 +1,17 @@
+//go:build !linux
+
+package snowflake
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+func (c *PTPClock) open(device string) error {
+	return fmt.Errorf("%s: PTP hardware clocks require Linux: %w", device, errors.ErrUnsupported)
+}
+
+func (c *PTPClock) read() (time.Time, error) {
+	return time.Time{}, errors.ErrUnsupported
+}
@@ -0,0 +1,82 @@
+package snowflake
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+var ErrQuotaExceeded = errors.New("caller quota exceeded")
+
+// QuotaManager enforces per-caller ID budgets inside one process, so a
+// runaway job cannot exhaust the sequence space other subsystems need.
+// Budgets are counted in fixed one-second windows per caller tag; tags
+// without a configured limit are unrestricted.
+type QuotaManager struct {
+	mu      sync.Mutex
+	limits  map[string]uint64
+	windows map[string]*quotaWindow
+}
+
+type quotaWindow struct {
+	start time.Time
+	count uint64
+}
+
+// NewQuotaManager creates a quota manager from per-tag limits in IDs per
+// second
+func NewQuotaManager(limits map[string]uint64) *QuotaManager {
+	q := &QuotaManager{
+		limits:  make(map[string]uint64, len(limits)),
+		windows: make(map[string]*quotaWindow, len(limits)),
+	}
+	for tag, limit := range limits {
+		q.limits[tag] = limit
+		q.windows[tag] = &quotaWindow{}
+	}
+	return q
+}
+
+// Allow consumes one ID from tag's budget, reporting false if the budget
+// for the current window is spent
+func (q *QuotaManager) Allow(tag string) bool {
+	return q.allowN(tag, 1)
+}
+
+// allowN consumes n IDs from tag's budget, all or nothing
+func (q *QuotaManager) allowN(tag string, n uint64) bool {
+	limit, ok := q.limits[tag]
+	if !ok {
+		return true
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	w := q.windows[tag]
+	if now := time.Now(); now.Sub(w.start) >= time.Second {
+		w.start = now
+		w.count = 0
+	}
+
+	if w.count+n > limit {
+		return false
+	}
+	w.count += n
+	return true
+}
+
+// releaseN returns n IDs consumed by Allow or allowN that were not issued
+// to tag's budget. IDs consumed in an earlier window are not carried
+// forward.
+func (q *QuotaManager) releaseN(tag string, n uint64) {
+	if _, ok := q.limits[tag]; !ok {
+		return
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	w := q.windows[tag]
+	w.count -= min(n, w.count)
+}
@@ -0,0 +1,127 @@
+package snowflake
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestQuotaManager(t *testing.T) {
+	quotas := NewQuotaManager(map[string]uint64{"backfill": 10})
+
+	gen, err := NewGenerator(Config{Version: Version0, NodeID: 1, Quotas: quotas})
+	if err != nil {
+		t.Fatalf("Failed to create generator: %v", err)
+	}
+
+	backfill := WithCallerTag(context.Background(), "backfill")
+	for i := 0; i < 10; i++ {
+		if _, err := gen.NextIDContext(backfill); err != nil {
+			t.Fatalf("ID %d within budget failed: %v", i, err)
+		}
+	}
+
+	if _, err := gen.NextIDContext(backfill); !errors.Is(err, ErrQuotaExceeded) {
+		t.Errorf("Expected ErrQuotaExceeded, got %v", err)
+	}
+
+	// Untagged and unlisted callers are unrestricted
+	api := WithCallerTag(context.Background(), "api")
+	for i := 0; i < 100; i++ {
+		if _, err := gen.NextIDContext(api); err != nil {
+			t.Fatalf("Unrestricted caller failed: %v", err)
+		}
+		if _, err := gen.NextID(); err != nil {
+			t.Fatalf("Untagged caller failed: %v", err)
+		}
+	}
+}
+
+func TestQuotaManager_Batch(t *testing.T) {
+	quotas := NewQuotaManager(map[string]uint64{"backfill": 10})
+
+	gen, err := NewGenerator(Config{Version: Version0, NodeID: 1, Quotas: quotas})
+	if err != nil {
+		t.Fatalf("Failed to create generator: %v", err)
+	}
+
+	ids, err := gen.NextIDsContext(WithCallerTag(context.Background(), "backfill"), 25)
+	if !errors.Is(err, ErrQuotaExceeded) {
+		t.Fatalf("Expected ErrQuotaExceeded, got %v", err)
+	}
+	if len(ids) != 10 {
+		t.Errorf("Expected partial batch of 10 IDs, got %d", len(ids))
+	}
+}
+
+func TestCallerTag(t *testing.T) {
+	if tag := CallerTag(context.Background()); tag != "" {
+		t.Errorf("Expected empty tag, got %q", tag)
+	}
+	if tag := CallerTag(WithCallerTag(context.Background(), "api")); tag != "api" {
+		t.Errorf("Expected tag api, got %q", tag)
+	}
+}
+
+func TestQuotaManager_Refund(t *testing.T) {
+	layout, err := NewLayoutBuilder().
+		Time(41, time.Millisecond, EpochY2020).
+		Field(FlagsField, 4).
+		Field("node", 8).
+		Sequence(10).
+		Build()
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		next func(*Generator) error
+	}{
+		{"NextID", func(g *Generator) error {
+			_, err := g.NextID()
+			return err
+		}},
+		{"NextIDWithFlags", func(g *Generator) error {
+			_, err := g.NextIDWithFlags(3)
+			return err
+		}},
+		{"NextIDs", func(g *Generator) error {
+			_, err := g.NextIDs(1)
+			return err
+		}},
+		{"Reserve", func(g *Generator) error {
+			_, err := g.Reserve(1)
+			return err
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var clockErr error
+			clock := ClockFunc(func() (time.Time, error) {
+				return time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC), clockErr
+			})
+			gen, err := NewGenerator(Config{
+				Layout: layout,
+				Clock:  clock,
+				Quotas: NewQuotaManager(map[string]uint64{"": 1}),
+			})
+			if err != nil {
+				t.Fatalf("Failed to create generator: %v", err)
+			}
+
+			clockErr = errors.New("clock unavailable")
+			if err := tt.next(gen); !errors.Is(err, clockErr) {
+				t.Fatalf("Expected the clock error, got %v", err)
+			}
+
+			// The failed call must not have spent the budget
+			clockErr = nil
+			if err := tt.next(gen); err != nil {
+				t.Errorf("Expected the refunded budget to cover the ID, got %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,342 @@
+package snowflake
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+var (
+	ErrVersionTaken    = errors.New("version already registered")
+	ErrReservedVersion = errors.New("version number reserved for built-in layouts")
+	ErrLayoutInUse     = errors.New("layout already used by a generator")
+)
+
+// VersionClass describes who may define layouts for a version number
+type VersionClass uint8
+
+const (
+	// VersionBuiltin numbers (0–3) are reserved for layouts shipped by
+	// this package and cannot be registered
+	VersionBuiltin VersionClass = iota
+
+	// VersionExperimental numbers (4–5) are for layouts under evaluation
+	// that may later be promoted to built-ins
+	VersionExperimental
+
+	// VersionUser numbers (6 and up) are for private layouts and will
+	// never be claimed by this package
+	VersionUser
+)
+
+// String returns the class name
+func (c VersionClass) String() string {
+	switch c {
+	case VersionBuiltin:
+		return "builtin"
+	case VersionExperimental:
+		return "experimental"
+	default:
+		return "user"
+	}
+}
+
+// ClassOf returns the version number class of v
+func ClassOf(v Version) VersionClass {
+	switch {
+	case v <= 3:
+		return VersionBuiltin
+	case v <= 5:
+		return VersionExperimental
+	default:
+		return VersionUser
+	}
+}
+
+// ClassOfLayout returns the class of the version prefix layout encodes,
+// judged by the 3-bit version number its top bits fall in: a 5-bit version
+// 6 (0b00110) lies in builtin Version1's prefix 0b001. Prefixes narrower
+// than 3 bits take the class of the lowest number they cover. Versionless
+// layouts have no prefix and are classed by their version number.
+func ClassOfLayout(layout *VersionLayout) VersionClass {
+	switch bits := layout.VersionBits; {
+	case bits == 0:
+		return ClassOf(layout.Version)
+	case bits >= 3:
+		return ClassOf(layout.Version >> (bits - 3))
+	default:
+		return ClassOf(layout.Version << (3 - bits))
+	}
+}
+
+// registryMu guards versionLayouts, usedVersions and updates to
+// decodeTable
+var registryMu sync.RWMutex
+
+// usedVersions records the versions generators have been created for
+var usedVersions = map[Version]bool{}
+
+// prefixTable indexes registered layouts by the top 3 bits of the IDs they
+// decode. A slot lists every layout whose prefix covers it: at most one
+// with a prefix of 3 bits or fewer, which appears in each slot it covers,
+// or any number of wider layouts sharing those 3 bits.
+type prefixTable [8][]*VersionLayout
+
+// decodeTable indexes versionLayouts for Decode, which reads it without
+// taking registryMu. It is replaced, never modified, whenever the registry
+// changes.
+var decodeTable atomic.Pointer[prefixTable]
+
+func init() {
+	rebuildDecodeTable()
+}
+
+// rebuildDecodeTable replaces decodeTable with an index of versionLayouts.
+// The caller must hold registryMu for writing, or be the package init.
+func rebuildDecodeTable() {
+	var table prefixTable
+	for _, layout := range versionLayouts {
+		switch bits := layout.VersionBits; {
+		case bits == 0:
+			// Versionless layouts are decoded with DecodeWithLayout only
+		case bits >= 3:
+			slot := layout.Version >> (bits - 3)
+			table[slot] = append(table[slot], layout)
+		default:
+			first := layout.Version << (3 - bits)
+			for slot := first; slot < first+1<<(3-bits); slot++ {
+				table[slot] = append(table[slot], layout)
+			}
+		}
+	}
+	decodeTable.Store(&table)
+}
+
+// RegisterLayout adds a layout to the registry so NewGenerator and Decode
+// can use it by version number. It is intended to be called from init
+// functions, letting external modules ship layouts without forking:
+//
+//	func init() {
+//		snowflake.MustRegisterLayout(&snowflake.VersionLayout{...})
+//	}
+//
+// Registration fails if the version number or prefix is reserved for
+// built-ins (see ClassOf and ClassOfLayout), if a user version number has a
+// prefix outside the user range, if the layout is invalid, if its version
+// number is taken, or if its version prefix overlaps that of a registered
+// layout of a different width (which would make Decode ambiguous). Private
+// layouts should use VersionUser numbers so they never conflict with
+// future official ones.
+func RegisterLayout(layout *VersionLayout) error {
+	if ClassOf(layout.Version) == VersionBuiltin {
+		return fmt.Errorf("%w: %d", ErrReservedVersion, layout.Version)
+	}
+	class := ClassOfLayout(layout)
+	if class == VersionBuiltin || ClassOf(layout.Version) == VersionUser && class != VersionUser {
+		return fmt.Errorf("%w: version %d in %d bits has a prefix in the %s range",
+			ErrReservedVersion, layout.Version, layout.VersionBits, class)
+	}
+	return registerLayout(layout)
+}
+
+// RegisterVersion is like RegisterLayout but takes the layout by value and
+// derives any zero maximums from the field widths, so a custom split only
+// needs its widths, epoch and time unit:
+//
+//	snowflake.RegisterVersion(snowflake.VersionLayout{
+//		Version: 6, VersionBits: 3, TimeBits: 41, NodeBits: 10, SequenceBits: 10,
+//		TimeUnit: time.Millisecond, Epoch: snowflake.EpochY2020,
+//	})
+func RegisterVersion(layout VersionLayout) error {
+	if layout.MaxTimestamp == 0 {
+		layout.MaxTimestamp = mask(layout.TimeBits)
+	}
+	if layout.MaxNodeID == 0 {
+		layout.MaxNodeID = mask(layout.NodeBits)
+	}
+	if layout.MaxSequence == 0 {
+		layout.MaxSequence = mask(layout.SequenceBits)
+	}
+	return RegisterLayout(&layout)
+}
+
+// minSplitTimeBits is the narrowest time field a Config bit split may
+// leave, about 17 years at millisecond resolution
+const minSplitTimeBits = 39
+
+// splitLayout returns Version0 with its node and sequence widths replaced,
+// under version v. Zero widths for both keep Version0's, and a non-zero
+// unit replaces its millisecond unit. If register is set, the split is
+// registered unless it already is; otherwise it is only checked to be
+// registrable.
+func splitLayout(v Version, nodeBits, sequenceBits uint8, unit time.Duration, register bool) (*VersionLayout, error) {
+	if ClassOf(v) == VersionBuiltin {
+		return nil, fmt.Errorf("%w: %d cannot be re-split", ErrReservedVersion, v)
+	}
+
+	base, _ := lookupLayout(Version0)
+	if nodeBits == 0 && sequenceBits == 0 {
+		nodeBits, sequenceBits = base.NodeBits, base.SequenceBits
+	}
+	// Units finer than a millisecond would shorten the minimum lifetime
+	// below what minSplitTimeBits promises
+	if unit == 0 {
+		unit = base.TimeUnit
+	} else if unit < time.Millisecond || unit%time.Millisecond != 0 {
+		return nil, fmt.Errorf("%w: time unit %s is not a whole number of milliseconds", ErrInvalidLayout, unit)
+	}
+	used := int(base.VersionBits) + int(nodeBits) + int(sequenceBits)
+	if used > 64-minSplitTimeBits {
+		return nil, fmt.Errorf("%w: %d node and %d sequence bits leave fewer than %d time bits",
+			ErrInvalidLayout, nodeBits, sequenceBits, minSplitTimeBits)
+	}
+
+	split := VersionLayout{
+		Version:      v,
+		VersionBits:  base.VersionBits,
+		TimeBits:     uint8(64 - used),
+		NodeBits:     nodeBits,
+		SequenceBits: sequenceBits,
+		TimeUnit:     unit,
+		Epoch:        base.Epoch,
+		MaxTimestamp: mask(uint8(64 - used)),
+		MaxNodeID:    mask(nodeBits),
+		MaxSequence:  mask(sequenceBits),
+	}
+
+	if !register {
+		if err := split.validate(); err != nil {
+			return nil, err
+		}
+		registryMu.RLock()
+		defer registryMu.RUnlock()
+
+		if registered, ok := versionLayouts[v]; ok && sameSplit(registered, &split) {
+			return &split, nil
+		}
+		if err := registrable(&split); err != nil {
+			return nil, err
+		}
+		return &split, nil
+	}
+
+	// Another generator may have registered the same split already
+	if err := registerLayout(&split); err != nil {
+		registered, ok := lookupLayout(v)
+		if !ok || !sameSplit(registered, &split) {
+			return nil, err
+		}
+	}
+	return &split, nil
+}
+
+// sameSplit reports whether two field-free layouts encode IDs identically
+func sameSplit(a, b *VersionLayout) bool {
+	return a.VersionBits == b.VersionBits && a.TimeBits == b.TimeBits &&
+		a.NodeBits == b.NodeBits && a.SequenceBits == b.SequenceBits &&
+		a.TimeUnit == b.TimeUnit && a.Epoch.Equal(b.Epoch) &&
+		len(a.Fields) == 0 && !a.NodeLast
+}
+
+// SetLayoutEpoch overrides the epoch of a registered layout, for forks of
+// a format that kept its bit split but chose a different epoch. It must be
+// called at process start, before any generator uses the version, and
+// fails with ErrLayoutInUse afterwards. Builtin versions may be
+// overridden too, but their IDs will then not match other processes'.
+func SetLayoutEpoch(v Version, epoch time.Time) error {
+	if epoch.IsZero() {
+		return fmt.Errorf("%w: version %d needs a non-zero epoch", ErrInvalidLayout, v)
+	}
+
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	layout, ok := versionLayouts[v]
+	if !ok {
+		return fmt.Errorf("%w: %d", ErrInvalidVersion, v)
+	}
+	if usedVersions[v] {
+		return fmt.Errorf("%w: %d", ErrLayoutInUse, v)
+	}
+
+	// Replace rather than modify, since callers may hold the old layout
+	updated := layout.clone()
+	updated.Epoch = epoch
+	versionLayouts[v] = updated
+	rebuildDecodeTable()
+	return nil
+}
+
+// markUsed records that a generator was created for version v
+func markUsed(v Version) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	usedVersions[v] = true
+}
+
+// MustRegisterLayout is like RegisterLayout but panics on error
+func MustRegisterLayout(layout *VersionLayout) {
+	if err := RegisterLayout(layout); err != nil {
+		panic(err)
+	}
+}
+
+// registerLayout validates and stores a copy of layout
+func registerLayout(layout *VersionLayout) error {
+	if err := layout.validate(); err != nil {
+		return err
+	}
+
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	if err := registrable(layout); err != nil {
+		return err
+	}
+	versionLayouts[layout.Version] = layout.clone()
+	rebuildDecodeTable()
+	return nil
+}
+
+// registrable checks that layout's version number, name and prefix are
+// free. The caller must hold registryMu.
+func registrable(layout *VersionLayout) error {
+	if _, exists := versionLayouts[layout.Version]; exists {
+		return fmt.Errorf("%w: %d", ErrVersionTaken, layout.Version)
+	}
+	for _, other := range versionLayouts {
+		if layout.Name != "" && strings.EqualFold(layout.Name, other.Name) {
+			return fmt.Errorf("%w: name %q is used by version %d", ErrVersionTaken, layout.Name, other.Version)
+		}
+		if prefixesOverlap(layout, other) {
+			return fmt.Errorf("%w: version %d/%d bits overlaps version %d/%d bits",
+				ErrVersionTaken, layout.Version, layout.VersionBits, other.Version, other.VersionBits)
+		}
+	}
+	return nil
+}
+
+// lookupLayout returns the registered layout for v
+func lookupLayout(v Version) (*VersionLayout, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	layout, ok := versionLayouts[v]
+	return layout, ok
+}
+
+// prefixesOverlap reports whether some ID would carry both layouts' version
+// prefixes. Versionless layouts have no prefix and never overlap.
+func prefixesOverlap(a, b *VersionLayout) bool {
+	if a.VersionBits == 0 || b.VersionBits == 0 {
+		return false
+	}
+	if a.VersionBits > b.VersionBits {
+		a, b = b, a
+	}
+	return uint64(b.Version)>>(b.VersionBits-a.VersionBits) == uint64(a.Version)
+}
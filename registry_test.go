@@ -0,0 +1,347 @@
+package snowflake
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func testLayout(v Version, versionBits uint8) *VersionLayout {
+	timeBits := 48 - versionBits
+	return &VersionLayout{
+		Version:      v,
+		VersionBits:  versionBits,
+		TimeBits:     timeBits,
+		NodeBits:     8,
+		SequenceBits: 8,
+		TimeUnit:     time.Millisecond,
+		Epoch:        EpochY2026,
+		MaxNodeID:    (1 << 8) - 1,
+		MaxSequence:  (1 << 8) - 1,
+		MaxTimestamp: mask(timeBits),
+	}
+}
+
+func TestRegisterLayout(t *testing.T) {
+	layout := testLayout(6, 3)
+	if err := RegisterLayout(layout); err != nil {
+		t.Fatalf("RegisterLayout failed: %v", err)
+	}
+	t.Cleanup(func() {
+		registryMu.Lock()
+		defer registryMu.Unlock()
+		delete(versionLayouts, 6)
+		rebuildDecodeTable()
+		delete(usedVersions, 6)
+	})
+
+	gen, err := NewGenerator(Config{Version: 6, NodeID: 5})
+	if err != nil {
+		t.Fatalf("Failed to create generator for registered layout: %v", err)
+	}
+
+	id, err := gen.NextID()
+	if err != nil {
+		t.Fatalf("Failed to generate ID: %v", err)
+	}
+
+	decoded, err := Decode(id)
+	if err != nil {
+		t.Fatalf("Failed to decode registered layout ID: %v", err)
+	}
+	if decoded.Version != 6 || decoded.NodeID != 5 {
+		t.Errorf("Unexpected decode: %s", decoded)
+	}
+
+	// Mutating the caller's copy must not affect the registry
+	layout.Epoch = EpochUnix
+	if registered, _ := LookupLayout(6); !registered.Epoch.Equal(EpochY2026) {
+		t.Error("Registry shares state with the caller's layout")
+	}
+}
+
+func TestRegisterLayout_Collisions(t *testing.T) {
+	withTestLayout(t, testLayout(6, 3))  // 0b110
+	withTestLayout(t, testLayout(14, 4)) // 0b1110
+
+	tests := []struct {
+		name   string
+		layout *VersionLayout
+	}{
+		{name: "duplicate version", layout: testLayout(6, 3)},
+		{name: "wider prefix overlaps version 6", layout: testLayout(13, 4)},    // 0b1101
+		{name: "narrower prefix overlaps version 14", layout: testLayout(7, 3)}, // 0b111
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := RegisterLayout(tt.layout); !errors.Is(err, ErrVersionTaken) {
+				t.Errorf("Expected ErrVersionTaken, got %v", err)
+			}
+		})
+	}
+}
+
+func TestRegisterLayout_ReservedVersions(t *testing.T) {
+	for v := Version(0); v <= 3; v++ {
+		if err := RegisterLayout(testLayout(v, 3)); !errors.Is(err, ErrReservedVersion) {
+			t.Errorf("Version %d: expected ErrReservedVersion, got %v", v, err)
+		}
+	}
+
+	// Wider version fields are classed by the 3-bit number of their prefix
+	tests := []struct {
+		name   string
+		layout *VersionLayout
+	}{
+		{"user number in builtin Version1's prefix", testLayout(6, 5)},  // 0b00110
+		{"user number in experimental prefix", testLayout(8, 4)},        // 0b1000
+		{"experimental number in builtin prefix", testLayout(4, 4)},     // 0b0100
+		{"user number in builtin Version2's prefix", testLayout(10, 5)}, // 0b01010
+	}
+	for _, tt := range tests {
+		if err := RegisterLayout(tt.layout); !errors.Is(err, ErrReservedVersion) {
+			t.Errorf("%s: expected ErrReservedVersion, got %v", tt.name, err)
+		}
+	}
+
+	wide := testLayout(13, 4) // 0b1101, inside user prefix 0b110
+	if err := RegisterLayout(wide); err != nil {
+		t.Fatalf("RegisterLayout failed for a user prefix: %v", err)
+	}
+	t.Cleanup(func() {
+		registryMu.Lock()
+		defer registryMu.Unlock()
+		delete(versionLayouts, 13)
+		rebuildDecodeTable()
+	})
+}
+
+func TestClassOfLayout(t *testing.T) {
+	tests := []struct {
+		layout *VersionLayout
+		want   VersionClass
+	}{
+		{testLayout(6, 3), VersionUser},
+		{testLayout(6, 5), VersionBuiltin},
+		{testLayout(8, 4), VersionExperimental},
+		{testLayout(14, 4), VersionUser},
+		{testLayout(2, 2), VersionExperimental},
+		{testLayout(3, 2), VersionUser},
+		{TwitterLayout(), VersionBuiltin},
+	}
+	for _, tt := range tests {
+		if got := ClassOfLayout(tt.layout); got != tt.want {
+			t.Errorf("ClassOfLayout(version %d in %d bits) = %s, want %s", tt.layout.Version, tt.layout.VersionBits, got, tt.want)
+		}
+	}
+}
+
+func TestClassOf(t *testing.T) {
+	want := []VersionClass{
+		VersionBuiltin, VersionBuiltin, VersionBuiltin, VersionBuiltin,
+		VersionExperimental, VersionExperimental,
+		VersionUser, VersionUser,
+	}
+	for v, class := range want {
+		if got := ClassOf(Version(v)); got != class {
+			t.Errorf("ClassOf(%d) = %s, want %s", v, got, class)
+		}
+	}
+}
+
+func TestDecode_SharedPrefix(t *testing.T) {
+	// 0b1101 and 0b11001 both fall under the 3-bit prefix 0b110
+	withTestLayout(t, testLayout(13, 4))
+	withTestLayout(t, testLayout(25, 5))
+
+	for _, v := range []Version{13, 25} {
+		gen, err := NewGenerator(Config{Version: v, NodeID: 7})
+		if err != nil {
+			t.Fatalf("Failed to create generator for version %d: %v", v, err)
+		}
+		id, _ := gen.NextID()
+		decoded, err := Decode(id)
+		if err != nil || decoded.Version != v || decoded.NodeID != 7 {
+			t.Errorf("Expected version %d node 7, got %v (%v)", v, decoded, err)
+		}
+	}
+
+	var unknown *UnknownVersionError
+	if _, err := Decode(0b11000 << 59); !errors.As(err, &unknown) {
+		t.Errorf("Expected UnknownVersionError for the free prefix 0b11000, got %v", err)
+	}
+}
+
+func TestMustRegisterLayout_PanicsOnDuplicate(t *testing.T) {
+	withTestLayout(t, testLayout(6, 3))
+
+	defer func() {
+		if recover() == nil {
+			t.Error("Expected MustRegisterLayout to panic")
+		}
+	}()
+	MustRegisterLayout(testLayout(6, 3))
+}
+
+func TestRegisterVersion(t *testing.T) {
+	err := RegisterVersion(VersionLayout{
+		Version:      6,
+		VersionBits:  3,
+		TimeBits:     41,
+		NodeBits:     10,
+		SequenceBits: 10,
+		TimeUnit:     time.Millisecond,
+		Epoch:        EpochY2020,
+	})
+	if err != nil {
+		t.Fatalf("RegisterVersion failed: %v", err)
+	}
+	t.Cleanup(func() {
+		registryMu.Lock()
+		defer registryMu.Unlock()
+		delete(versionLayouts, 6)
+		rebuildDecodeTable()
+		delete(usedVersions, 6)
+	})
+
+	layout, _ := LookupLayout(6)
+	if layout.MaxNodeID != 1023 || layout.MaxSequence != 1023 || layout.MaxTimestamp != mask(41) {
+		t.Errorf("Expected derived maximums, got %+v", layout)
+	}
+
+	gen, err := NewGenerator(Config{Version: 6, NodeID: 1000})
+	if err != nil {
+		t.Fatalf("Failed to create generator: %v", err)
+	}
+	id, _ := gen.NextID()
+	if decoded, err := Decode(id); err != nil || decoded.NodeID != 1000 {
+		t.Errorf("Expected node 1000 from Decode, got %v (%v)", decoded, err)
+	}
+
+	if err := RegisterVersion(VersionLayout{Version: 7, VersionBits: 3, TimeBits: 40, TimeUnit: time.Millisecond}); !errors.Is(err, ErrInvalidLayout) {
+		t.Errorf("Expected ErrInvalidLayout for short layout, got %v", err)
+	}
+}
+
+func TestNewGenerator_BitSplit(t *testing.T) {
+	t.Cleanup(func() {
+		registryMu.Lock()
+		defer registryMu.Unlock()
+		delete(versionLayouts, 6)
+		rebuildDecodeTable()
+		delete(usedVersions, 6)
+	})
+
+	gen, err := NewGenerator(Config{Version: 6, NodeID: 1023, NodeBits: 10, SequenceBits: 12})
+	if err != nil {
+		t.Fatalf("Failed to create generator: %v", err)
+	}
+	id, _ := gen.NextID()
+
+	decoded, err := Decode(id)
+	if err != nil {
+		t.Fatalf("Decode failed: %v", err)
+	}
+	if decoded.Version != 6 || decoded.NodeID != 1023 {
+		t.Errorf("Unexpected decode: %s", decoded)
+	}
+	if diff := time.Since(decoded.Time); diff < 0 || diff > time.Second {
+		t.Errorf("Decoded time seems incorrect: %v (diff: %v)", decoded.Time, diff)
+	}
+
+	layout, _ := LookupLayout(6)
+	if layout.TimeBits != 39 || layout.MaxSequence != 4095 || !layout.Epoch.Equal(EpochY2026) {
+		t.Errorf("Unexpected split layout: %+v", layout)
+	}
+
+	// The same split may be requested again; a different one may not
+	if _, err := NewGenerator(Config{Version: 6, NodeID: 1, NodeBits: 10, SequenceBits: 12}); err != nil {
+		t.Errorf("Expected the same split to be accepted, got %v", err)
+	}
+	if _, err := NewGenerator(Config{Version: 6, NodeBits: 12, SequenceBits: 10}); !errors.Is(err, ErrVersionTaken) {
+		t.Errorf("Expected ErrVersionTaken for a different split, got %v", err)
+	}
+}
+
+func TestNewGenerator_BitSplitInvalid(t *testing.T) {
+	if _, err := NewGenerator(Config{Version: Version0, NodeBits: 10, SequenceBits: 12}); !errors.Is(err, ErrReservedVersion) {
+		t.Errorf("Expected ErrReservedVersion for a builtin, got %v", err)
+	}
+	if _, err := NewGenerator(Config{Version: 7, NodeBits: 12, SequenceBits: 12}); !errors.Is(err, ErrInvalidLayout) {
+		t.Errorf("Expected ErrInvalidLayout for too few time bits, got %v", err)
+	}
+	if _, ok := LookupLayout(7); ok {
+		t.Error("Rejected split should not be registered")
+	}
+}
+
+func TestNewGenerator_TimeUnitSplit(t *testing.T) {
+	t.Cleanup(func() {
+		registryMu.Lock()
+		defer registryMu.Unlock()
+		delete(versionLayouts, 6)
+		rebuildDecodeTable()
+		delete(usedVersions, 6)
+	})
+
+	gen, err := NewGenerator(Config{Version: 6, NodeID: 255, TimeUnit: 10 * time.Millisecond})
+	if err != nil {
+		t.Fatalf("Failed to create generator: %v", err)
+	}
+	id, _ := gen.NextID()
+
+	decoded, err := Decode(id)
+	if err != nil {
+		t.Fatalf("Decode failed: %v", err)
+	}
+	if decoded.NodeID != 255 || decoded.Time.UnixMilli()%10 != 0 {
+		t.Errorf("Unexpected decode: %s", decoded)
+	}
+
+	// Same bits as Version0, ten times the lifetime
+	layout, _ := LookupLayout(6)
+	base, _ := LookupLayout(Version0)
+	if layout.TimeBits != base.TimeBits || layout.NodeBits != base.NodeBits || layout.TimeUnit != 10*time.Millisecond {
+		t.Errorf("Unexpected split layout: %+v", layout)
+	}
+	if got, want := layout.ExhaustionTime().Year()-2026, 10*(base.ExhaustionTime().Year()-2026); got < want-10 || got > want+10 {
+		t.Errorf("Expected about %d years, got %d", want, got)
+	}
+
+	for _, unit := range []time.Duration{time.Microsecond, 1500 * time.Microsecond, -time.Second} {
+		if _, err := NewGenerator(Config{Version: 7, TimeUnit: unit}); !errors.Is(err, ErrInvalidLayout) {
+			t.Errorf("Expected ErrInvalidLayout for unit %s, got %v", unit, err)
+		}
+	}
+}
+
+func TestSetLayoutEpoch(t *testing.T) {
+	fork := *versionLayouts[Version0]
+	fork.Version = 6
+	withTestLayout(t, &fork)
+
+	if err := SetLayoutEpoch(6, EpochY2020); err != nil {
+		t.Fatalf("SetLayoutEpoch failed: %v", err)
+	}
+	if layout, _ := LookupLayout(6); !layout.Epoch.Equal(EpochY2020) {
+		t.Errorf("Expected epoch %s, got %s", EpochY2020, layout.Epoch)
+	}
+
+	gen, err := NewGenerator(Config{Version: 6, NodeID: 1})
+	if err != nil {
+		t.Fatalf("Failed to create generator: %v", err)
+	}
+	id, _ := gen.NextID()
+	decoded, _ := Decode(id)
+	if diff := time.Since(decoded.Time); diff < 0 || diff > time.Second {
+		t.Errorf("Decoded time seems incorrect: %v (diff: %v)", decoded.Time, diff)
+	}
+
+	if err := SetLayoutEpoch(6, EpochY2026); !errors.Is(err, ErrLayoutInUse) {
+		t.Errorf("Expected ErrLayoutInUse once a generator exists, got %v", err)
+	}
+	if err := SetLayoutEpoch(7, EpochY2026); !errors.Is(err, ErrInvalidVersion) {
+		t.Errorf("Expected ErrInvalidVersion for an unregistered version, got %v", err)
+	}
+}
@@ -0,0 +1,59 @@
+package snowflake
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestRecorderReplayer(t *testing.T) {
+	gen, _ := NewGenerator(Config{Version: Version0, NodeID: 2})
+
+	var buf bytes.Buffer
+	rec := NewRecorder(gen, &buf)
+
+	var recorded []uint64
+	for i := 0; i < 5; i++ {
+		id, err := rec.NextID()
+		if err != nil {
+			t.Fatalf("Failed to record ID: %v", err)
+		}
+		recorded = append(recorded, id)
+	}
+	if err := rec.Flush(); err != nil {
+		t.Fatalf("Failed to flush: %v", err)
+	}
+
+	for run := 0; run < 2; run++ {
+		var src IDSource
+		src, err := NewReplayer(bytes.NewReader(buf.Bytes()))
+		if err != nil {
+			t.Fatalf("Failed to read recording: %v", err)
+		}
+		for i, want := range recorded {
+			got, err := src.NextID()
+			if err != nil || got != want {
+				t.Fatalf("Run %d, ID %d: expected %d, got %d (%v)", run, i, want, got, err)
+			}
+		}
+		if _, err := src.NextID(); !errors.Is(err, ErrReplayExhausted) {
+			t.Errorf("Expected ErrReplayExhausted, got %v", err)
+		}
+	}
+}
+
+func TestNewReplayer_AuditLogAndErrors(t *testing.T) {
+	audit := "42\t2026-05-01T12:00:00Z\tbackfill\n\n43\t2026-05-01T12:00:00Z\tbackfill\n"
+	p, err := NewReplayer(strings.NewReader(audit))
+	if err != nil {
+		t.Fatalf("Failed to read audit log: %v", err)
+	}
+	if p.Remaining() != 2 {
+		t.Errorf("Expected 2 IDs, got %d", p.Remaining())
+	}
+
+	if _, err := NewReplayer(strings.NewReader("42\nnope\n")); !errors.Is(err, ErrInvalidID) {
+		t.Errorf("Expected ErrInvalidID, got %v", err)
+	}
+}
@@ -0,0 +1,141 @@
+package snowflake
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ErrBlockClosed is returned when committing a reserved block that was
+// already committed or released
+var ErrBlockClosed = errors.New("reserved block already committed or released")
+
+// ReservedBlock is a set of IDs claimed by Reserve. The IDs are unique and
+// will never be handed out again, but they only count as issued (in Stats
+// and the audit log) once the block is committed. Blocks that are released
+// instead are recorded in the audit log as uncommitted.
+//
+// The usual pattern for two-phase workflows is:
+//
+//	block, err := gen.Reserve(n)
+//	...
+//	defer block.Release()
+//	// use block.IDs() inside the transaction
+//	if err := tx.Commit(); err == nil {
+//		block.Commit()
+//	}
+type ReservedBlock struct {
+	g   *Generator
+	ids []uint64
+	tag string
+	at  time.Time // clock reading when the block was reserved
+
+	mu     sync.Mutex
+	closed bool
+}
+
+// Reserve claims n IDs without issuing them
+func (g *Generator) Reserve(n int) (*ReservedBlock, error) {
+	return g.ReserveContext(context.Background(), n)
+}
+
+// ReserveContext claims n IDs on behalf of the caller tagged in ctx. Unlike
+// NextIDsContext it is all or nothing: if the reservation cannot be
+// completed, the IDs claimed so far are released and only the error is
+// returned.
+func (g *Generator) ReserveContext(ctx context.Context, n int) (*ReservedBlock, error) {
+	if n < 0 {
+		return nil, fmt.Errorf("invalid reservation size: %d", n)
+	}
+
+	block := &ReservedBlock{g: g, ids: make([]uint64, 0, n), tag: CallerTag(ctx)}
+	if err := g.reserve(ctx, block, n); err != nil {
+		block.Release()
+		return nil, err
+	}
+
+	return block, nil
+}
+
+func (g *Generator) reserve(ctx context.Context, block *ReservedBlock, n int) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	for len(block.ids) < n {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if g.quotas != nil && !g.quotas.Allow(block.tag) {
+			return ErrQuotaExceeded
+		}
+
+		id, err := g.nextID()
+		if err != nil {
+			if g.quotas != nil {
+				g.quotas.releaseN(block.tag, 1)
+			}
+			return err
+		}
+		block.ids = append(block.ids, id)
+		block.at = g.lastRead
+	}
+
+	return nil
+}
+
+// IDs returns the reserved IDs in increasing order
+func (b *ReservedBlock) IDs() []uint64 {
+	return append([]uint64(nil), b.ids...)
+}
+
+// Len returns the number of reserved IDs
+func (b *ReservedBlock) Len() int {
+	return len(b.ids)
+}
+
+// Commit marks the block's IDs as issued and records them in the audit log
+func (b *ReservedBlock) Commit() error {
+	if !b.close() {
+		return ErrBlockClosed
+	}
+
+	b.g.mu.Lock()
+	b.g.issued += uint64(len(b.ids))
+	b.g.mu.Unlock()
+
+	b.record(false)
+	return nil
+}
+
+// Release abandons the block, recording its IDs in the audit log as
+// uncommitted. The IDs are not reused. Release is a no-op once the block
+// has been committed or released, so it is safe to defer.
+func (b *ReservedBlock) Release() {
+	if b.close() {
+		b.record(true)
+	}
+}
+
+// close moves the block to its final state, reporting whether it was open
+func (b *ReservedBlock) close() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		return false
+	}
+	b.closed = true
+	return true
+}
+
+func (b *ReservedBlock) record(uncommitted bool) {
+	if b.g.audit == nil {
+		return
+	}
+
+	for _, id := range b.ids {
+		b.g.audit.record(AuditEntry{ID: id, Time: b.at, Tag: b.tag, Uncommitted: uncommitted})
+	}
+}
@@ -0,0 +1,120 @@
+package snowflake
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestReserve_Commit(t *testing.T) {
+	var buf bytes.Buffer
+	audit := NewAuditLog(&buf, 16)
+
+	gen, err := NewGenerator(Config{Version: Version0, NodeID: 1, Audit: audit})
+	if err != nil {
+		t.Fatalf("Failed to create generator: %v", err)
+	}
+
+	block, err := gen.ReserveContext(WithCallerTag(context.Background(), "orders"), 10)
+	if err != nil {
+		t.Fatalf("Failed to reserve: %v", err)
+	}
+	if issued := gen.Stats().Issued; issued != 0 {
+		t.Errorf("Expected reserved IDs not to count as issued, got %d", issued)
+	}
+
+	ids := block.IDs()
+	if len(ids) != 10 || block.Len() != 10 {
+		t.Fatalf("Expected 10 reserved IDs, got %d", len(ids))
+	}
+	for i := 1; i < len(ids); i++ {
+		if ids[i] <= ids[i-1] {
+			t.Fatalf("IDs not increasing at %d: %d <= %d", i, ids[i], ids[i-1])
+		}
+	}
+
+	next, err := gen.NextID()
+	if err != nil {
+		t.Fatalf("Failed to generate ID: %v", err)
+	}
+	if next <= ids[len(ids)-1] {
+		t.Errorf("Expected ID after reservation to exceed %d, got %d", ids[len(ids)-1], next)
+	}
+
+	if err := block.Commit(); err != nil {
+		t.Fatalf("Failed to commit: %v", err)
+	}
+	if issued := gen.Stats().Issued; issued != 11 {
+		t.Errorf("Expected 11 issued IDs, got %d", issued)
+	}
+	if err := block.Commit(); !errors.Is(err, ErrBlockClosed) {
+		t.Errorf("Expected ErrBlockClosed on second commit, got %v", err)
+	}
+	block.Release()
+
+	if err := audit.Close(); err != nil {
+		t.Fatalf("Failed to close audit log: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 11 {
+		t.Fatalf("Expected 11 audit lines, got %d", len(lines))
+	}
+	for _, line := range lines {
+		if strings.HasSuffix(line, "uncommitted") {
+			t.Errorf("Unexpected uncommitted line: %q", line)
+		}
+	}
+}
+
+func TestReserve_Release(t *testing.T) {
+	var buf bytes.Buffer
+	audit := NewAuditLog(&buf, 16)
+
+	gen, err := NewGenerator(Config{Version: Version0, NodeID: 1, Audit: audit})
+	if err != nil {
+		t.Fatalf("Failed to create generator: %v", err)
+	}
+
+	block, err := gen.Reserve(3)
+	if err != nil {
+		t.Fatalf("Failed to reserve: %v", err)
+	}
+	block.Release()
+
+	if err := block.Commit(); !errors.Is(err, ErrBlockClosed) {
+		t.Errorf("Expected ErrBlockClosed after release, got %v", err)
+	}
+	if issued := gen.Stats().Issued; issued != 0 {
+		t.Errorf("Expected released IDs not to count as issued, got %d", issued)
+	}
+
+	if err := audit.Close(); err != nil {
+		t.Fatalf("Failed to close audit log: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("Expected 3 audit lines, got %d", len(lines))
+	}
+	for _, line := range lines {
+		if fields := strings.Split(line, "\t"); len(fields) != 4 || fields[3] != "uncommitted" {
+			t.Errorf("Expected uncommitted record, got %q", line)
+		}
+	}
+}
+
+func TestReserve_QuotaIsAllOrNothing(t *testing.T) {
+	gen, err := NewGenerator(Config{
+		Version: Version0,
+		Quotas:  NewQuotaManager(map[string]uint64{"": 5}),
+	})
+	if err != nil {
+		t.Fatalf("Failed to create generator: %v", err)
+	}
+
+	block, err := gen.Reserve(10)
+	if !errors.Is(err, ErrQuotaExceeded) || block != nil {
+		t.Errorf("Expected ErrQuotaExceeded and no block, got %v, %v", block, err)
+	}
+}
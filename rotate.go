@@ -0,0 +1,119 @@
+package snowflake
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// RotatingFile is an append-only file that rotates once it reaches a size
+// limit, for use as an AuditLog writer. On rotation the file is renamed
+// path.1, earlier rotations move up one number, the oldest beyond keep is
+// removed, and a new file is started at path.
+//
+// Rotation happens only at line boundaries, so a file may exceed the limit
+// by the rest of the line that crossed it. RotatingFile is not safe for
+// concurrent use; AuditLog writes to it from a single goroutine.
+type RotatingFile struct {
+	path     string
+	maxBytes int64
+	keep     int
+
+	f       *os.File
+	size    int64
+	midLine bool
+}
+
+// OpenRotatingFile opens path for appending, creating it if needed. It
+// rotates the file at maxBytes, keeping up to keep rotated files.
+func OpenRotatingFile(path string, maxBytes int64, keep int) (*RotatingFile, error) {
+	if maxBytes < 1 || keep < 1 {
+		return nil, fmt.Errorf("invalid rotation: %d bytes, keeping %d files", maxBytes, keep)
+	}
+
+	r := &RotatingFile{path: path, maxBytes: maxBytes, keep: keep}
+	if err := r.open(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Write appends p, rotating first whenever the file has reached its limit
+// at the end of a line
+func (r *RotatingFile) Write(p []byte) (int, error) {
+	written := 0
+	for len(p) > 0 {
+		if r.size >= r.maxBytes && !r.midLine {
+			if err := r.rotate(); err != nil {
+				return written, err
+			}
+		}
+
+		// Write up to the end of the line that reaches the limit
+		chunk := p
+		if from := max(r.maxBytes-r.size-1, 0); int64(len(p)) > from {
+			if i := bytes.IndexByte(p[from:], '\n'); i >= 0 {
+				chunk = p[:from+int64(i)+1]
+			}
+		}
+
+		n, err := r.f.Write(chunk)
+		written += n
+		r.size += int64(n)
+		if n > 0 {
+			r.midLine = chunk[n-1] != '\n'
+		}
+		if err != nil {
+			return written, err
+		}
+		p = p[n:]
+	}
+	return written, nil
+}
+
+// Close closes the current file
+func (r *RotatingFile) Close() error {
+	return r.f.Close()
+}
+
+// open opens path for appending and records its size
+func (r *RotatingFile) open() error {
+	f, err := os.OpenFile(r.path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o644)
+	if err != nil {
+		return err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return err
+	}
+
+	r.f, r.size, r.midLine = f, info.Size(), false
+	return nil
+}
+
+// rotate shifts path and its rotations up one number and reopens path
+func (r *RotatingFile) rotate() error {
+	if err := r.f.Close(); err != nil {
+		return err
+	}
+
+	if err := os.Remove(r.rotated(r.keep)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	for i := r.keep - 1; i >= 1; i-- {
+		if err := os.Rename(r.rotated(i), r.rotated(i+1)); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+	}
+	if err := os.Rename(r.path, r.rotated(1)); err != nil {
+		return err
+	}
+	return r.open()
+}
+
+// rotated returns the name of the ith most recent rotation
+func (r *RotatingFile) rotated(i int) string {
+	return fmt.Sprintf("%s.%d", r.path, i)
+}
@@ -0,0 +1,99 @@
+package snowflake
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRotatingFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+	r, err := OpenRotatingFile(path, 10, 2)
+	if err != nil {
+		t.Fatalf("OpenRotatingFile failed: %v", err)
+	}
+
+	// Lines crossing the limit, even across writes, are finished before
+	// rotating, so no line is split across files
+	for _, chunk := range []string{"aaaa\nbb", "bbbbbbb\n", "cc\n", "dd\nee\n", "ffffffffffff\n", "g\n", "hhhhhhhhhhh\n", "i\n"} {
+		if _, err := r.Write([]byte(chunk)); err != nil {
+			t.Fatalf("Write %q failed: %v", chunk, err)
+		}
+	}
+	if err := r.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+
+	want := map[string]string{
+		path:        "i\n",
+		path + ".1": "g\nhhhhhhhhhhh\n",
+		path + ".2": "cc\ndd\nee\nffffffffffff\n",
+	}
+	for name, content := range want {
+		data, err := os.ReadFile(name)
+		if err != nil || string(data) != content {
+			t.Errorf("%s: expected %q, got %q (%v)", filepath.Base(name), content, data, err)
+		}
+	}
+	if _, err := os.Stat(path + ".3"); err == nil {
+		t.Error("Expected the oldest rotation beyond keep to be removed")
+	}
+}
+
+func TestRotatingFile_AuditLog(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+	r, err := OpenRotatingFile(path, 256, 100)
+	if err != nil {
+		t.Fatalf("OpenRotatingFile failed: %v", err)
+	}
+	audit := NewAuditLog(r, 16)
+
+	gen, err := NewGenerator(Config{Version: Version0, NodeID: 1, Audit: audit})
+	if err != nil {
+		t.Fatalf("Failed to create generator: %v", err)
+	}
+	const numIDs = 200
+	for i := 0; i < numIDs; i++ {
+		if _, err := gen.NextIDTagged("backfill"); err != nil {
+			t.Fatalf("Failed to generate ID: %v", err)
+		}
+	}
+	if err := audit.Close(); err != nil {
+		t.Fatalf("Failed to close audit log: %v", err)
+	}
+	r.Close()
+
+	files, _ := filepath.Glob(path + "*")
+	if len(files) < 2 {
+		t.Fatalf("Expected the log to rotate, got %v", files)
+	}
+
+	lines := 0
+	for _, name := range files {
+		data, _ := os.ReadFile(name)
+		if !bytes.HasSuffix(data, []byte("\n")) {
+			t.Errorf("%s ends mid-line", filepath.Base(name))
+		}
+		for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+			if len(strings.Split(line, "\t")) != 3 {
+				t.Errorf("%s: malformed line %q", filepath.Base(name), line)
+			}
+			lines++
+		}
+	}
+	if lines != numIDs {
+		t.Errorf("Expected %d lines across all files, got %d", numIDs, lines)
+	}
+}
+
+func TestOpenRotatingFile_Invalid(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+	if _, err := OpenRotatingFile(path, 0, 1); err == nil {
+		t.Error("Expected an error for a zero size limit")
+	}
+	if _, err := OpenRotatingFile(path, 10, 0); err == nil {
+		t.Error("Expected an error for keeping no rotations")
+	}
+}
@@ -0,0 +1,59 @@
+package snowflake
+
+import "fmt"
+
+// SchemaFormat selects how IDs are represented in API schemas
+type SchemaFormat uint8
+
+const (
+	// SchemaString documents IDs as decimal strings, safe for clients that
+	// parse JSON numbers as doubles (JavaScript, many JSON libraries)
+	SchemaString SchemaFormat = iota
+
+	// SchemaInt64 documents IDs as 64-bit integers. Only IDs below 2^63
+	// are representable, which holds for every builtin version.
+	SchemaInt64
+)
+
+// Schema is a JSON Schema / OpenAPI schema object for an ID. It marshals
+// directly into OpenAPI 3 components:
+//
+//	components:
+//	  schemas:
+//	    SnowflakeID: <IDSchema(SchemaString)>
+type Schema struct {
+	Type        string `json:"type" yaml:"type"`
+	Format      string `json:"format,omitempty" yaml:"format,omitempty"`
+	Pattern     string `json:"pattern,omitempty" yaml:"pattern,omitempty"`
+	Minimum     *int64 `json:"minimum,omitempty" yaml:"minimum,omitempty"`
+	Description string `json:"description,omitempty" yaml:"description,omitempty"`
+	Example     any    `json:"example,omitempty" yaml:"example,omitempty"`
+}
+
+// exampleID is the Version0 ID for node 7 at 2026-03-01T00:00:00Z,
+// sequence 1, used in schema examples
+const exampleID uint64 = 334076313601793
+
+// IDSchema returns the schema documenting IDs in the given format
+func IDSchema(format SchemaFormat) (Schema, error) {
+	switch format {
+	case SchemaString:
+		return Schema{
+			Type:        "string",
+			Pattern:     "^[0-9]{1,20}$",
+			Description: "Snowflake ID as an unsigned 64-bit decimal string",
+			Example:     ID(exampleID).String(),
+		}, nil
+	case SchemaInt64:
+		var zero int64
+		return Schema{
+			Type:        "integer",
+			Format:      "int64",
+			Minimum:     &zero,
+			Description: "Snowflake ID",
+			Example:     exampleID,
+		}, nil
+	default:
+		return Schema{}, fmt.Errorf("unknown schema format %d", format)
+	}
+}
@@ -0,0 +1,43 @@
+package snowflake
+
+import (
+	"encoding/json"
+	"regexp"
+	"testing"
+	"time"
+)
+
+func TestIDSchema(t *testing.T) {
+	decoded, err := Decode(exampleID)
+	if err != nil {
+		t.Fatalf("Failed to decode example ID: %v", err)
+	}
+	if !decoded.Time.Equal(time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)) || decoded.NodeID != 7 {
+		t.Errorf("Unexpected example ID components: %s", decoded)
+	}
+
+	str, err := IDSchema(SchemaString)
+	if err != nil {
+		t.Fatalf("Failed to build string schema: %v", err)
+	}
+	pattern := regexp.MustCompile(str.Pattern)
+	for _, s := range []string{str.Example.(string), "18446744073709551615"} {
+		if !pattern.MatchString(s) {
+			t.Errorf("Pattern %s rejects %s", str.Pattern, s)
+		}
+	}
+
+	num, err := IDSchema(SchemaInt64)
+	if err != nil {
+		t.Fatalf("Failed to build int64 schema: %v", err)
+	}
+	data, _ := json.Marshal(num)
+	want := `{"type":"integer","format":"int64","minimum":0,"description":"Snowflake ID","example":334076313601793}`
+	if string(data) != want {
+		t.Errorf("Expected %s, got %s", want, data)
+	}
+
+	if _, err := IDSchema(SchemaFormat(9)); err == nil {
+		t.Error("Expected error for unknown format")
+	}
+}
@@ -0,0 +1,21 @@
+package snowflake
+
+// ShardField is the name of the optional custom field holding a logical
+// shard key, so the database shard owning a row can be derived from its ID
+// alone. Add it to a layout with LayoutBuilder.Field(ShardField, bits) and
+// set it per call with NextIDWithShard.
+const ShardField = "shard"
+
+// NextIDWithShard generates the next ID with its shard field set to
+// shardID. The shard sits above the node and sequence, so IDs issued
+// within the same time unit for different shards are not ordered by issue
+// time.
+func (g *Generator) NextIDWithShard(shardID uint64) (uint64, error) {
+	return g.nextIDWithField(ShardField, shardID)
+}
+
+// Shard returns the ID's shard field. It reports false if the layout has
+// no shard field.
+func (d *DecodedID) Shard() (uint64, bool) {
+	return d.Field(ShardField)
+}
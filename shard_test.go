@@ -0,0 +1,44 @@
+package snowflake
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestNextIDWithShard(t *testing.T) {
+	layout, err := NewLayoutBuilder().
+		Time(41, time.Millisecond, EpochY2020).
+		Field(ShardField, 13).
+		Sequence(10).
+		Build()
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+
+	gen, err := NewGenerator(Config{Layout: layout})
+	if err != nil {
+		t.Fatalf("Failed to create generator: %v", err)
+	}
+
+	seen := make(map[uint64]bool)
+	for shard := uint64(0); shard < 100; shard++ {
+		id, err := gen.NextIDWithShard(shard * 81)
+		if err != nil {
+			t.Fatalf("NextIDWithShard failed: %v", err)
+		}
+		if seen[id] {
+			t.Fatalf("Duplicate ID %d", id)
+		}
+		seen[id] = true
+
+		decoded, _ := DecodeWithLayout(id, layout)
+		if got, ok := decoded.Shard(); !ok || got != shard*81 {
+			t.Fatalf("Expected shard %d, got %d (%v)", shard*81, got, ok)
+		}
+	}
+
+	if _, err := gen.NextIDWithShard(1 << 13); !errors.Is(err, ErrComponentRange) {
+		t.Errorf("Expected ErrComponentRange for a shard beyond 13 bits, got %v", err)
+	}
+}
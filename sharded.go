@@ -0,0 +1,138 @@
+package snowflake
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ShardedGenerator issues IDs for layouts with a ShardField, keeping a
+// separate sequence per shard: each shard gets the full sequence space
+// every time unit, as in Instagram's scheme (see InstagramLayout). IDs of
+// different shards never collide since their shard fields differ.
+//
+// Shards share the node ID, so they share cfg.StateStore and one boot
+// nonce: the store keeps the highest mark any shard saved, which every
+// shard resumes above after a restart. The mark is loaded once, when the
+// ShardedGenerator is created; shards created later start from it rather
+// than from marks their siblings saved since, which they need not wait
+// out as their shard fields differ.
+type ShardedGenerator struct {
+	cfg       Config
+	bootNonce uint64
+
+	mu     sync.Mutex
+	shards map[uint64]*Generator
+}
+
+// NewShardedGenerator validates cfg and returns a generator creating a
+// Generator per shard on first use. cfg must not set the shard field
+// itself.
+func NewShardedGenerator(cfg Config) (*ShardedGenerator, error) {
+	if _, set := cfg.Fields[ShardField]; set {
+		return nil, fmt.Errorf("%w: ShardedGenerator sets the %s field", ErrInvalidLayout, ShardField)
+	}
+	r, err := cfg.resolve(true)
+	if err != nil {
+		return nil, err
+	}
+	if _, _, ok := r.layout.field(ShardField); !ok {
+		return nil, fmt.Errorf("%w: version %d has no %s field", ErrInvalidLayout, r.layout.Version, ShardField)
+	}
+
+	if cfg.StateStore != nil {
+		shared, err := newSharedStateStore(cfg.StateStore, cfg.stateTimeout())
+		if err != nil {
+			return nil, err
+		}
+		// Fail on another node's state now rather than on the first shard
+		if _, err := loadState(shared, r.layout, r.nodeID, cfg.stateTimeout()); err != nil {
+			return nil, err
+		}
+		cfg.StateStore = shared
+	}
+	return &ShardedGenerator{cfg: cfg, bootNonce: newBootNonce(), shards: make(map[uint64]*Generator)}, nil
+}
+
+// NextID generates the next ID for shardID
+func (s *ShardedGenerator) NextID(shardID uint64) (uint64, error) {
+	gen, err := s.Shard(shardID)
+	if err != nil {
+		return 0, err
+	}
+	return gen.NextID()
+}
+
+// Shard returns the generator for shardID, creating it if necessary
+func (s *ShardedGenerator) Shard(shardID uint64) (*Generator, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if gen, ok := s.shards[shardID]; ok {
+		return gen, nil
+	}
+
+	cfg := s.cfg
+	cfg.Fields = make(map[string]uint64, len(s.cfg.Fields)+1)
+	for name, value := range s.cfg.Fields {
+		cfg.Fields[name] = value
+	}
+	cfg.Fields[ShardField] = shardID
+
+	gen, err := NewGenerator(cfg)
+	if err != nil {
+		return nil, err
+	}
+	gen.bootNonce = s.bootNonce
+	s.shards[shardID] = gen
+	return gen, nil
+}
+
+// sharedStateStore lets shards share a StateStore. Loads return the state
+// saved before startup, and saves only ever raise the mark, so a shard
+// behind the others cannot lower the mark they resume above.
+type sharedStateStore struct {
+	store   StateStore
+	startup State
+	found   bool // whether there was a startup state
+
+	mu    sync.Mutex
+	until time.Time
+}
+
+// newSharedStateStore loads store's state once for the shards to share
+func newSharedStateStore(store StateStore, timeout time.Duration) (*sharedStateStore, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	s, err := store.Load(ctx)
+	if err != nil && !errors.Is(err, ErrNoState) {
+		return nil, fmt.Errorf("loading state: %w", err)
+	}
+	return &sharedStateStore{store: store, startup: s, found: err == nil, until: s.Until}, nil
+}
+
+// Load implements StateStore
+func (s *sharedStateStore) Load(ctx context.Context) (State, error) {
+	if !s.found {
+		return State{}, ErrNoState
+	}
+	return s.startup, nil
+}
+
+// Save implements StateStore
+func (s *sharedStateStore) Save(ctx context.Context, state State) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !state.Until.After(s.until) {
+		return nil
+	}
+	if err := s.store.Save(ctx, state); err != nil {
+		return err
+	}
+	s.until = state.Until
+	return nil
+}
@@ -0,0 +1,118 @@
+package snowflake
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestShardedGenerator(t *testing.T) {
+	layout := InstagramLayout()
+	gen, err := NewShardedGenerator(Config{Layout: layout})
+	if err != nil {
+		t.Fatalf("Failed to create sharded generator: %v", err)
+	}
+
+	seen := make(map[uint64]bool)
+	for i := 0; i < 2000; i++ {
+		shard := uint64(i % 2)
+		id, err := gen.NextID(shard)
+		if err != nil {
+			t.Fatalf("NextID failed: %v", err)
+		}
+		if seen[id] {
+			t.Fatalf("Duplicate ID %d", id)
+		}
+		seen[id] = true
+
+		decoded, _ := DecodeWithLayout(id, layout)
+		if got, _ := decoded.Shard(); got != shard {
+			t.Fatalf("Expected shard %d, got %d", shard, got)
+		}
+	}
+
+	// Each shard counts its own sequence from zero
+	a, _ := gen.Shard(7)
+	b, _ := gen.Shard(8)
+	idA, _ := a.NextID()
+	idB, _ := b.NextID()
+	decodedA, _ := DecodeWithLayout(idA, layout)
+	decodedB, _ := DecodeWithLayout(idB, layout)
+	if decodedA.Sequence != 0 || decodedB.Sequence != 0 {
+		t.Errorf("Expected fresh shards to start at sequence 0, got %d and %d", decodedA.Sequence, decodedB.Sequence)
+	}
+
+	if _, err := gen.NextID(1 << 13); !errors.Is(err, ErrInvalidLayout) {
+		t.Errorf("Expected ErrInvalidLayout for a shard beyond 13 bits, got %v", err)
+	}
+}
+
+func TestNewShardedGenerator_Errors(t *testing.T) {
+	if _, err := NewShardedGenerator(Config{Version: Version0}); !errors.Is(err, ErrInvalidLayout) {
+		t.Errorf("Expected ErrInvalidLayout for a layout without a shard field, got %v", err)
+	}
+	cfg := Config{Layout: InstagramLayout(), Fields: map[string]uint64{ShardField: 1}}
+	if _, err := NewShardedGenerator(cfg); !errors.Is(err, ErrInvalidLayout) {
+		t.Errorf("Expected ErrInvalidLayout when Fields sets the shard, got %v", err)
+	}
+}
+
+func TestShardedGenerator_StateStore(t *testing.T) {
+	store := FileStateStore{Path: filepath.Join(t.TempDir(), "state.json")}
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	clock := ClockFunc(func() (time.Time, error) { return now, nil })
+	gen, err := NewShardedGenerator(Config{Layout: InstagramLayout(), Clock: clock, StateStore: store})
+	if err != nil {
+		t.Fatalf("Failed to create sharded generator: %v", err)
+	}
+
+	a, _ := gen.Shard(1)
+	b, _ := gen.Shard(2)
+	if a.BootNonce() != b.BootNonce() {
+		t.Errorf("Expected shards to share a boot nonce, got %d and %d", a.BootNonce(), b.BootNonce())
+	}
+
+	if _, err := a.NextID(); err != nil {
+		t.Fatalf("NextID failed: %v", err)
+	}
+	ahead, _ := store.Load(context.Background())
+
+	// A shard saving behind another must not lower the shared mark
+	now = now.Add(-time.Minute)
+	if _, err := b.NextID(); err != nil {
+		t.Fatalf("NextID failed: %v", err)
+	}
+	if saved, err := store.Load(context.Background()); err != nil || !saved.Until.Equal(ahead.Until) {
+		t.Errorf("Expected the mark to stay at %s, got %+v (%v)", ahead.Until, saved, err)
+	}
+}
+
+func TestShardedGenerator_StateStoreLatency(t *testing.T) {
+	store := FileStateStore{Path: filepath.Join(t.TempDir(), "state.json")}
+	gen, err := NewShardedGenerator(Config{Layout: InstagramLayout(), StateStore: store, StateWindow: time.Second})
+	if err != nil {
+		t.Fatalf("Failed to create sharded generator: %v", err)
+	}
+
+	// New shards must not wait out the marks their siblings just saved
+	for shard := range uint64(4) {
+		start := time.Now()
+		if _, err := gen.NextID(shard); err != nil {
+			t.Fatalf("NextID failed: %v", err)
+		}
+		if d := time.Since(start); d > 100*time.Millisecond {
+			t.Errorf("Shard %d took %s for its first ID", shard, d)
+		}
+	}
+
+	// Another version's state fails up front
+	other := FileStateStore{Path: filepath.Join(t.TempDir(), "other.json")}
+	if err := other.Save(context.Background(), State{Version: 7}); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	if _, err := NewShardedGenerator(Config{Layout: InstagramLayout(), StateStore: other}); !errors.Is(err, ErrStateMismatch) {
+		t.Errorf("Expected ErrStateMismatch, got %v", err)
+	}
+}
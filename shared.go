@@ -0,0 +1,135 @@
+package snowflake
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"os"
+	"sync/atomic"
+	"unsafe"
+)
+
+// ErrSharedStateMismatch is returned when a shared state file was set up
+// for a different layout or node ID
+var ErrSharedStateMismatch = errors.New("shared state belongs to another layout or node")
+
+// sharedStateSize is the size of the mapped state: a layout fingerprint
+// followed by the last timestamp and sequence packed into one word
+const sharedStateSize = 16
+
+// SharedGenerator is an experimental generator whose last timestamp and
+// sequence live in a memory-mapped file, so several processes on one host
+// can issue IDs under a single node ID without a coordinating daemon.
+// Processes claim IDs with a compare-and-swap on the shared word, never
+// holding a lock, so a process that dies mid-call cannot wedge the others.
+//
+// Every process must open the same file (ideally on tmpfs, e.g. under
+// /dev/shm) with the same layout and node ID. Only the layout, node, clock,
+// floor and wait settings of the Config apply; quotas, auditing, hooks on
+// NextID and batch options are ignored. It requires a Unix system.
+type SharedGenerator struct {
+	gen   *Generator
+	file  *os.File
+	mem   []byte
+	state *uint64
+}
+
+// NewSharedGenerator maps the state file at path, creating it if needed,
+// and returns a generator sharing it
+func NewSharedGenerator(path string, cfg Config) (*SharedGenerator, error) {
+	gen, err := NewGenerator(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0o600)
+	if err != nil {
+		return nil, err
+	}
+	if err := f.Truncate(sharedStateSize); err != nil {
+		f.Close()
+		return nil, err
+	}
+	mem, err := mapShared(f, sharedStateSize)
+	if err != nil {
+		f.Close()
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	g := &SharedGenerator{
+		gen:   gen,
+		file:  f,
+		mem:   mem,
+		state: (*uint64)(unsafe.Pointer(&mem[8])),
+	}
+
+	// The first process to map the file claims it for its layout and node
+	header := (*uint64)(unsafe.Pointer(&mem[0]))
+	want := gen.fingerprint()
+	if !atomic.CompareAndSwapUint64(header, 0, want) && atomic.LoadUint64(header) != want {
+		g.Close()
+		return nil, fmt.Errorf("%w: %s", ErrSharedStateMismatch, path)
+	}
+	return g, nil
+}
+
+// NextID generates the next unique ID across all processes sharing the
+// state file
+func (g *SharedGenerator) NextID() (uint64, error) {
+	seqBits := g.gen.layout.SequenceBits
+	maxSeq := g.gen.layout.MaxSequence
+
+	for {
+		timestamp, err := g.gen.usableTimestamp()
+		if err != nil {
+			return 0, err
+		}
+
+		old := atomic.LoadUint64(g.state)
+		last, sequence := old>>seqBits, old&maxSeq
+
+		switch {
+		case timestamp < last:
+			// Clock rollback, or another process read a later clock
+			g.gen.pause(last)
+			continue
+		case timestamp == last:
+			if sequence == maxSeq {
+				g.gen.pause(last + 1)
+				continue
+			}
+			sequence++
+		default:
+			sequence = 0
+		}
+
+		if atomic.CompareAndSwapUint64(g.state, old, timestamp<<seqBits|sequence) {
+			return g.gen.encode(timestamp, sequence), nil
+		}
+	}
+}
+
+// Close unmaps the state file. The file itself is left for other
+// processes and later runs.
+func (g *SharedGenerator) Close() error {
+	err := unmapShared(g.mem)
+	if cerr := g.file.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+// fingerprint identifies the generator's layout and node, so processes
+// configured differently cannot share state. It is never zero.
+func (g *Generator) fingerprint() uint64 {
+	l := g.layout
+	h := fnv.New64a()
+	var buf []byte
+	buf = append(buf, byte(l.Version), l.VersionBits, l.TimeBits, l.NodeBits, l.SequenceBits)
+	buf = binary.BigEndian.AppendUint64(buf, uint64(l.TimeUnit))
+	buf = binary.BigEndian.AppendUint64(buf, uint64(l.Epoch.UnixNano()))
+	buf = binary.BigEndian.AppendUint64(buf, g.encode(0, 0))
+	h.Write(buf)
+	return h.Sum64() | 1
+}
@@ -0,0 +1,16 @@
+//go:build !unix
+
+package snowflake
+
+import (
+	"errors"
+	"os"
+)
+
+func mapShared(f *os.File, size int) ([]byte, error) {
+	return nil, errors.ErrUnsupported
+}
+
+func unmapShared(mem []byte) error {
+	return errors.ErrUnsupported
+}
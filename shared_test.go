@@ -0,0 +1,74 @@
+//go:build unix
+
+package snowflake
+
+import (
+	"errors"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+func TestSharedGenerator(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "node-7")
+
+	// Separate mappings of one file stand in for separate processes
+	gens := make([]*SharedGenerator, 4)
+	for i := range gens {
+		g, err := NewSharedGenerator(path, Config{NodeID: 7})
+		if err != nil {
+			t.Fatalf("Failed to create shared generator: %v", err)
+		}
+		defer g.Close()
+		gens[i] = g
+	}
+
+	const perGen = 5000
+	ids := make([][]uint64, len(gens))
+	var wg sync.WaitGroup
+	for i, g := range gens {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range perGen {
+				id, err := g.NextID()
+				if err != nil {
+					t.Errorf("NextID failed: %v", err)
+					return
+				}
+				ids[i] = append(ids[i], id)
+			}
+		}()
+	}
+	wg.Wait()
+
+	seen := make(map[uint64]bool, len(gens)*perGen)
+	for _, batch := range ids {
+		for i, id := range batch {
+			if seen[id] {
+				t.Fatalf("Duplicate ID across shared generators: %d", id)
+			}
+			seen[id] = true
+			if i > 0 && id <= batch[i-1] {
+				t.Fatalf("IDs not increasing within a generator: %d then %d", batch[i-1], id)
+			}
+		}
+	}
+}
+
+func TestSharedGenerator_Mismatch(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state")
+
+	g, err := NewSharedGenerator(path, Config{NodeID: 1})
+	if err != nil {
+		t.Fatalf("Failed to create shared generator: %v", err)
+	}
+	defer g.Close()
+
+	if _, err := NewSharedGenerator(path, Config{NodeID: 2}); !errors.Is(err, ErrSharedStateMismatch) {
+		t.Errorf("Expected ErrSharedStateMismatch for another node, got %v", err)
+	}
+	if _, err := NewSharedGenerator(path, Config{Version: Version2, NodeID: 1}); !errors.Is(err, ErrSharedStateMismatch) {
+		t.Errorf("Expected ErrSharedStateMismatch for another layout, got %v", err)
+	}
+}
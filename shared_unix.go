@@ -0,0 +1,16 @@
+//go:build unix
+
+package snowflake
+
+import (
+	"os"
+	"syscall"
+)
+
+func mapShared(f *os.File, size int) ([]byte, error) {
+	return syscall.Mmap(int(f.Fd()), 0, size, syscall.PROT_READ|syscall.PROT_WRITE, syscall.MAP_SHARED)
+}
+
+func unmapShared(mem []byte) error {
+	return syscall.Munmap(mem)
+}
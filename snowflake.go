@@ -1,8 +1,13 @@
 package snowflake
 
 import (
+	"crypto/rand"
+	"encoding/binary"
 	"errors"
 	"fmt"
+	"math"
+	"math/bits"
+	"sort"
 	"sync"
 	"time"
 )
@@ -11,9 +16,17 @@ import (
 type Version uint8
 
 const (
-	// Version0 layout: [3 bits version][40 bits time][10 bits node][11 bits sequence]
+	// Version0 layout: [3 bits version][45 bits time][8 bits node][8 bits sequence]
 	// Time unit: milliseconds, Epoch: 2026-01-01T00:00:00Z
 	Version0 Version = 0
+
+	// Version2 layout: [3 bits version][50 bits time][7 bits node][4 bits sequence]
+	// Time unit: microseconds, Epoch: 2026-01-01T00:00:00Z
+	Version2 Version = 2
+
+	// Version3 layout: [3 bits version][32 bits time][16 bits node][13 bits sequence]
+	// Time unit: seconds, Epoch: 2026-01-01T00:00:00Z
+	Version3 Version = 3
 )
 
 var (
@@ -21,11 +34,51 @@ var (
 	ErrInvalidVersion    = errors.New("unsupported version")
 	ErrClockRollback     = errors.New("clock moved backwards")
 	ErrSequenceExhausted = errors.New("sequence exhausted for current millisecond")
+	ErrClockBeforeFloor  = errors.New("system clock is before configured floor")
+	ErrEpochInFuture     = errors.New("layout epoch is in the future")
+	ErrHorizonReached    = errors.New("remaining layout lifetime below configured minimum")
+	ErrTimestampOverflow = errors.New("timestamp overflow for version")
 )
 
+// TimestampOverflowError reports that the clock has passed the last time a
+// layout can encode. It matches ErrTimestampOverflow with errors.Is.
+type TimestampOverflowError struct {
+	Version Version
+
+	// Exhausted is the first instant the layout's time field cannot hold
+	Exhausted time.Time
+}
+
+func (e *TimestampOverflowError) Error() string {
+	return fmt.Sprintf("%v %d: time field exhausted at %s", ErrTimestampOverflow, e.Version, e.Exhausted.Format(time.RFC3339))
+}
+
+// Unwrap returns ErrTimestampOverflow
+func (e *TimestampOverflowError) Unwrap() error {
+	return ErrTimestampOverflow
+}
+
+// UnknownVersionError reports that no registered layout matches an ID's
+// version prefix. It matches ErrInvalidVersion with errors.Is.
+type UnknownVersionError struct {
+	// Value is the ID's top three bits, the version field as the builtin
+	// layouts read it; layouts with narrower or wider fields differ
+	Value Version
+}
+
+func (e *UnknownVersionError) Error() string {
+	return fmt.Sprintf("%v: no layout registered for version prefix %d", ErrInvalidVersion, e.Value)
+}
+
+// Unwrap returns ErrInvalidVersion
+func (e *UnknownVersionError) Unwrap() error {
+	return ErrInvalidVersion
+}
+
 // VersionLayout defines the bit layout and constraints for a version
 type VersionLayout struct {
 	Version      Version
+	Name         string // optional alias accepted by ParseVersion, e.g. "micro"
 	VersionBits  uint8
 	TimeBits     uint8
 	NodeBits     uint8
@@ -35,6 +88,14 @@ type VersionLayout struct {
 	MaxNodeID    uint64
 	MaxSequence  uint64
 	MaxTimestamp uint64
+
+	// Fields are custom named fields between time and node, most
+	// significant first. See LayoutBuilder.
+	Fields []Field
+
+	// NodeLast places the node field below the sequence, as Sonyflake does:
+	// [version][time][fields][sequence][node]
+	NodeLast bool
 }
 
 // Version layouts registry
@@ -46,31 +107,237 @@ var versionLayouts = map[Version]*VersionLayout{
 		NodeBits:     8,
 		SequenceBits: 8,
 		TimeUnit:     time.Millisecond,
-		Epoch:        time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+		Epoch:        EpochY2026,
 		MaxNodeID:    (1 << 8) - 1,  // 255
 		MaxSequence:  (1 << 8) - 1,  // 255
 		MaxTimestamp: (1 << 45) - 1, // ~1,118 years
 	},
+	Version2: {
+		Version:      Version2,
+		Name:         "micro",
+		VersionBits:  3,
+		TimeBits:     50,
+		NodeBits:     7,
+		SequenceBits: 4,
+		TimeUnit:     time.Microsecond,
+		Epoch:        EpochY2026,
+		MaxNodeID:    (1 << 7) - 1,  // 127
+		MaxSequence:  (1 << 4) - 1,  // 15
+		MaxTimestamp: (1 << 50) - 1, // ~35 years, until 2061
+	},
+	Version3: {
+		Version:      Version3,
+		Name:         "seconds",
+		VersionBits:  3,
+		TimeBits:     32,
+		NodeBits:     16,
+		SequenceBits: 13,
+		TimeUnit:     time.Second,
+		Epoch:        EpochY2026,
+		MaxNodeID:    (1 << 16) - 1, // 65535
+		MaxSequence:  (1 << 13) - 1, // 8191
+		MaxTimestamp: (1 << 32) - 1, // ~136 years, until 2162
+	},
+}
+
+// LookupLayout returns a copy of the registered layout for v
+func LookupLayout(v Version) (VersionLayout, bool) {
+	layout, ok := lookupLayout(v)
+	if !ok {
+		return VersionLayout{}, false
+	}
+	return *layout.clone(), true
+}
+
+// Layouts returns copies of all registered layouts, ordered by version
+func Layouts() []VersionLayout {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	layouts := make([]VersionLayout, 0, len(versionLayouts))
+	for _, layout := range versionLayouts {
+		layouts = append(layouts, *layout.clone())
+	}
+	sort.Slice(layouts, func(i, j int) bool {
+		return layouts[i].Version < layouts[j].Version
+	})
+	return layouts
+}
+
+// WaitStrategy controls how a generator waits for its clock to advance
+// after sequence exhaustion or a clock rollback
+type WaitStrategy uint8
+
+const (
+	// WaitPoll re-reads the clock every 100µs until it advances
+	WaitPoll WaitStrategy = iota
+
+	// WaitSleep sleeps for the full remainder of the awaited time unit
+	// before re-reading the clock, minimizing wakeups on battery-powered
+	// devices at the cost of slightly later resumption. The clock is read
+	// once before and once after each sleep.
+	WaitSleep
+)
+
+// String returns the strategy name
+func (w WaitStrategy) String() string {
+	switch w {
+	case WaitPoll:
+		return "poll"
+	case WaitSleep:
+		return "sleep"
+	default:
+		return fmt.Sprintf("WaitStrategy(%d)", uint8(w))
+	}
+}
+
+// parseWaitStrategy parses a strategy name as returned by String
+func parseWaitStrategy(s string) (WaitStrategy, error) {
+	switch s {
+	case "poll":
+		return WaitPoll, nil
+	case "sleep":
+		return WaitSleep, nil
+	default:
+		return 0, fmt.Errorf("unknown wait strategy %q", s)
+	}
 }
 
 // Config holds generator configuration
 type Config struct {
 	Version Version
 	NodeID  uint64
+
+	// Layout, when set, is used instead of the registered layout for
+	// Version. This allows unregistered layouts, such as versionless ones
+	// whose IDs must be decoded with DecodeWithLayout.
+	Layout *VersionLayout
+
+	// NodeBits and SequenceBits, when either is set, split Version0's bits
+	// differently: the version field and epoch stay, the time field gets
+	// the remaining bits. The split is registered under Version, which
+	// must not be a builtin, so Decode understands its IDs; processes that
+	// only decode must register the same split with RegisterVersion.
+	// Ignored when Layout is set.
+	NodeBits     uint8
+	SequenceBits uint8
+
+	// TimeUnit, when set, replaces Version0's millisecond unit in the same
+	// kind of split, keeping its node and sequence widths unless NodeBits
+	// or SequenceBits are set too. It must be a whole number of
+	// milliseconds; coarser units such as 10ms or 1s stretch the ID
+	// space's lifetime by the same factor. Ignored when Layout is set.
+	TimeUnit time.Duration
+
+	// NotBefore is an optional floor for the system clock (e.g. the
+	// deployment date). When set, the generator refuses to start or issue
+	// IDs while the clock reports an earlier time, catching hosts booted
+	// with an epoch-zero clock before they mint garbage IDs.
+	NotBefore time.Time
+
+	// Fields sets the layout's custom fields to fixed values. Unset fields
+	// are zero.
+	Fields map[string]uint64
+
+	// Audit, when set, receives a record of every issued ID
+	Audit *AuditLog
+
+	// WaitStrategy selects how to wait for the clock (default: WaitPoll)
+	WaitStrategy WaitStrategy
+
+	// Fair serves concurrent callers in FIFO order so a hot goroutine
+	// cannot starve others, at some cost in throughput under contention
+	Fair bool
+
+	// Quotas, when set, enforces per-caller ID budgets keyed by caller tag
+	Quotas *QuotaManager
+
+	// AllowPreEpoch lets NewGenerator succeed while the clock is before the
+	// layout's epoch, reporting it via Hooks.OnPreEpoch instead of failing
+	// with ErrEpochInFuture. NextID still fails until the epoch passes.
+	AllowPreEpoch bool
+
+	// MinRemaining caps how close to the end of the layout's time range the
+	// generator will operate. Once less than this much lifetime remains it
+	// fails with ErrHorizonReached, prompting a planned version migration
+	// instead of a surprise overflow.
+	MinRemaining time.Duration
+
+	// StateStore, when set, persists a high-water mark StateWindow ahead
+	// of the latest issued ID. A new generator loads it and issues nothing
+	// below the mark, so restarts are safe even if the clock went back.
+	// IDs wait for a save once per window, for at most StateTimeout; a
+	// store slower than that fails the save and the ID.
+	StateStore   StateStore
+	StateWindow  time.Duration // default: 1s
+	StateTimeout time.Duration // default: 1s
+
+	// LifetimeWarning is the fraction of the layout's time range, between
+	// 0 and 1, after which Hooks.OnLifetimeWarning fires, e.g. 0.8 to hear
+	// about exhaustion while a fifth of the range remains. Zero disables it.
+	LifetimeWarning float64
+
+	// Hooks are optional callbacks for generator events
+	Hooks Hooks
+
+	// Clock is the generator's time source (default: SystemClock)
+	Clock Clock
+
+	// Source selects the label, registered with RegisterSource, recorded
+	// in the layout's source field. It is shorthand for setting
+	// Fields[SourceField] to the label's value.
+	Source string
+
+	// DatacenterID and WorkerID address generators in layouts with a
+	// datacenter field (see DatacenterLayout). DatacenterID is shorthand
+	// for setting Fields[DatacenterField]; WorkerID is another name for
+	// NodeID, and only one of the two may be set.
+	DatacenterID uint64
+	WorkerID     uint64
+
+	// PositiveInt64 guarantees every ID fits a signed 64-bit integer, for
+	// BIGINT columns and languages without unsigned types. NewGenerator
+	// rejects layouts whose version or time field could set the top bit.
+	PositiveInt64 bool
+
+	// SmearBatches spreads each NextIDs batch that spans several time units
+	// evenly across them, instead of filling the first unit's sequence
+	// space before moving on, for consumers that bucket IDs by timestamp
+	SmearBatches bool
 }
 
 // Generator is a thread-safe Snowflake ID generator
 type Generator struct {
-	mu            sync.Mutex
+	mu            sync.Locker
 	layout        *VersionLayout
 	nodeID        uint64
+	notBefore     time.Time
 	lastTimestamp uint64
+	lastRead      time.Time // clock reading behind the last issued ID
 	sequence      uint64
-
-	// Bit shift positions for encoding
-	versionShift uint8
-	timeShift    uint8
-	nodeShift    uint8
+	bootNonce     uint64
+	issued        uint64
+	audit         *AuditLog
+	waitStrategy  WaitStrategy
+	clock         Clock
+	quotas        *QuotaManager
+	smear         bool
+	hooks         Hooks
+	horizon       uint64 // last timestamp allowed by Config.MinRemaining
+	warnAt        uint64 // timestamp firing OnLifetimeWarning, or 0 once fired
+	stateStore    StateStore
+	stateWindow   uint64 // in time units
+	stateUntil    uint64 // timestamp of the saved high-water mark
+	stateTimeout  time.Duration
+
+	// Precomputed version and custom field bits, and shift positions for
+	// encoding [version][time][fields][node][sequence] (or, with NodeLast,
+	// [sequence][node])
+	versionPrefix uint64
+	fieldBits     uint64
+	timeShift     uint8
+	nodeShift     uint8
+	seqShift      uint8
 }
 
 // DecodedID contains the components of a decoded Snowflake ID
@@ -80,55 +347,335 @@ type DecodedID struct {
 	NodeID    uint64
 	Sequence  uint64
 	Time      time.Time
+
+	// Fields holds the values of custom layout fields by name. It is nil
+	// for layouts without custom fields.
+	Fields map[string]uint64
 }
 
-// NewGenerator creates a new Snowflake ID generator
-func NewGenerator(cfg Config) (*Generator, error) {
-	layout, ok := versionLayouts[cfg.Version]
+// resolvedConfig is a validated Config, ready to build a generator from
+type resolvedConfig struct {
+	layout    *VersionLayout
+	nodeID    uint64
+	clock     Clock
+	now       time.Time
+	horizon   uint64
+	fieldBits uint64
+}
+
+// Validate performs the checks NewGenerator does without creating a
+// generator, for deploy-time preflight checks. It reads the configured
+// clock and loads the StateStore's state, if set, so an unreachable store
+// or one saved by another node fails here, but does not call hooks or
+// register bit splits.
+func (cfg Config) Validate() error {
+	r, err := cfg.resolve(false)
+	if err != nil || cfg.StateStore == nil {
+		return err
+	}
+	_, err = loadState(cfg.StateStore, r.layout, r.nodeID, cfg.stateTimeout())
+	return err
+}
+
+// resolve validates cfg, registering its bit split if register is set
+func (cfg Config) resolve(register bool) (*resolvedConfig, error) {
+	layout, ok := lookupLayout(cfg.Version)
+	if cfg.Layout != nil {
+		layout, ok = cfg.Layout.clone(), true
+	} else if cfg.NodeBits != 0 || cfg.SequenceBits != 0 || cfg.TimeUnit != 0 {
+		split, err := splitLayout(cfg.Version, cfg.NodeBits, cfg.SequenceBits, cfg.TimeUnit, register)
+		if err != nil {
+			return nil, err
+		}
+		layout, ok = split, true
+	}
 	if !ok {
 		return nil, fmt.Errorf("%w: %d", ErrInvalidVersion, cfg.Version)
 	}
 
-	if cfg.NodeID > layout.MaxNodeID {
-		return nil, fmt.Errorf("%w: %d (max: %d)", ErrInvalidNodeID, cfg.NodeID, layout.MaxNodeID)
+	if err := layout.validate(); err != nil {
+		return nil, err
+	}
+
+	if cfg.PositiveInt64 && !layout.positive() {
+		return nil, fmt.Errorf("%w: version %d IDs can set the sign bit", ErrInvalidLayout, layout.Version)
+	}
+
+	nodeID := cfg.NodeID
+	if cfg.WorkerID != 0 {
+		if nodeID != 0 && nodeID != cfg.WorkerID {
+			return nil, fmt.Errorf("%w: NodeID %d and WorkerID %d both set", ErrInvalidNodeID, nodeID, cfg.WorkerID)
+		}
+		nodeID = cfg.WorkerID
+	}
+	if nodeID > layout.MaxNodeID {
+		return nil, fmt.Errorf("%w: %d (max: %d)", ErrInvalidNodeID, nodeID, layout.MaxNodeID)
+	}
+
+	clock := cfg.Clock
+	if clock == nil {
+		clock = SystemClock
+	}
+	now, err := clock.Now()
+	if err != nil {
+		return nil, err
+	}
+
+	if !cfg.NotBefore.IsZero() && now.Before(cfg.NotBefore) {
+		return nil, fmt.Errorf("%w: now %s, floor %s", ErrClockBeforeFloor,
+			now.Format(time.RFC3339), cfg.NotBefore.Format(time.RFC3339))
+	}
+
+	if now.Before(layout.Epoch) && !cfg.AllowPreEpoch {
+		return nil, fmt.Errorf("%w: now %s, epoch %s", ErrEpochInFuture,
+			now.Format(time.RFC3339), layout.Epoch.Format(time.RFC3339))
+	}
+
+	// An epoch so far back that no timestamp from now on fits, or that
+	// the elapsed time saturates time.Duration, can never issue an ID
+	if now.Sub(layout.Epoch) == math.MaxInt64 {
+		return nil, fmt.Errorf("%w: version %d epoch %s is more than 292 years ago",
+			ErrInvalidLayout, layout.Version, layout.Epoch.Format(time.RFC3339))
+	}
+	if !now.Before(layout.ExhaustionTime()) {
+		return nil, &TimestampOverflowError{Version: layout.Version, Exhausted: layout.ExhaustionTime()}
+	}
+
+	if cfg.LifetimeWarning < 0 || cfg.LifetimeWarning >= 1 {
+		return nil, fmt.Errorf("invalid lifetime warning %g: must be in [0, 1)", cfg.LifetimeWarning)
+	}
+
+	horizon := layout.MaxTimestamp
+	if cfg.MinRemaining > 0 {
+		reserved := uint64(cfg.MinRemaining / layout.TimeUnit)
+		if reserved > horizon || now.After(layout.timeOf(horizon-reserved)) {
+			return nil, fmt.Errorf("%w: %s remaining, %s required", ErrHorizonReached,
+				layout.ExhaustionTime().Sub(now).Round(time.Second), cfg.MinRemaining)
+		}
+		horizon -= reserved
 	}
 
-	// Calculate bit shifts for encoding
-	// Layout from MSB to LSB: [version][time][node][sequence]
-	sequenceBits := layout.SequenceBits
-	nodeBits := layout.NodeBits
-	timeBits := layout.TimeBits
-	// versionBits := uint8(3) // Always 3 bits for version
+	// Source and DatacenterID are shorthands for setting their fields
+	shorthands := map[string]uint64{}
+	if cfg.Source != "" {
+		value, ok := sourceValue(cfg.Source)
+		if !ok {
+			return nil, fmt.Errorf("%w: %q", ErrUnknownSource, cfg.Source)
+		}
+		shorthands[SourceField] = value
+	}
+	if cfg.DatacenterID != 0 {
+		shorthands[DatacenterField] = cfg.DatacenterID
+	}
+
+	fields := cfg.Fields
+	if len(shorthands) > 0 {
+		fields = make(map[string]uint64, len(cfg.Fields)+len(shorthands))
+		for name, v := range cfg.Fields {
+			fields[name] = v
+		}
+		for name, v := range shorthands {
+			if _, set := fields[name]; set {
+				return nil, fmt.Errorf("%w: Fields[%q] set along with its shorthand", ErrInvalidLayout, name)
+			}
+			fields[name] = v
+		}
+	}
+
+	for _, derived := range []string{TombstoneField, ChildField} {
+		if _, set := fields[derived]; set {
+			return nil, fmt.Errorf("%w: generators cannot set the %s field", ErrInvalidLayout, derived)
+		}
+	}
+
+	var fieldBits uint64
+	for name, value := range fields {
+		shift, bits, ok := layout.field(name)
+		if !ok {
+			return nil, fmt.Errorf("%w: version %d has no field %q", ErrInvalidLayout, layout.Version, name)
+		}
+		if value > mask(bits) {
+			return nil, fmt.Errorf("%w: field %q value %d exceeds %d bits", ErrInvalidLayout, name, value, bits)
+		}
+		fieldBits |= value << shift
+	}
+
+	return &resolvedConfig{
+		layout:    layout,
+		nodeID:    nodeID,
+		clock:     clock,
+		now:       now,
+		horizon:   horizon,
+		fieldBits: fieldBits,
+	}, nil
+}
+
+// NewGenerator creates a new Snowflake ID generator
+func NewGenerator(cfg Config) (*Generator, error) {
+	r, err := cfg.resolve(true)
+	if err != nil {
+		return nil, err
+	}
+	layout := r.layout
+
+	if r.now.Before(layout.Epoch) && cfg.Hooks.OnPreEpoch != nil {
+		cfg.Hooks.OnPreEpoch(r.now, layout.Epoch)
+	}
+
+	// Resume above the saved high-water mark, if any
+	var stateWindow, stateUntil uint64
+	if cfg.StateStore != nil {
+		window := cfg.StateWindow
+		if window <= 0 {
+			window = defaultStateWindow
+		}
+		stateWindow = max(uint64(window/layout.TimeUnit), 1)
+		if stateUntil, err = loadState(cfg.StateStore, layout, r.nodeID, cfg.stateTimeout()); err != nil {
+			return nil, err
+		}
+	}
+
+	// A generator started past the threshold warns right away
+	var warnAt uint64
+	if cfg.LifetimeWarning > 0 && cfg.Hooks.OnLifetimeWarning != nil {
+		warnAt = max(uint64(float64(layout.MaxTimestamp)*cfg.LifetimeWarning), 1)
+		if !r.now.Before(layout.Epoch) {
+			if now := uint64(r.now.Sub(layout.Epoch) / layout.TimeUnit); now >= warnAt {
+				cfg.Hooks.OnLifetimeWarning(float64(now)/float64(layout.MaxTimestamp), layout.ExhaustionTime())
+				warnAt = 0
+			}
+		}
+	}
+
+	var mu sync.Locker = &sync.Mutex{}
+	if cfg.Fair {
+		mu = newTicketLock()
+	}
 
 	g := &Generator{
+		mu:            mu,
 		layout:        layout,
-		nodeID:        cfg.NodeID,
-		lastTimestamp: 0,
+		nodeID:        r.nodeID,
+		notBefore:     cfg.NotBefore,
+		audit:         cfg.Audit,
+		waitStrategy:  cfg.WaitStrategy,
+		clock:         r.clock,
+		quotas:        cfg.Quotas,
+		smear:         cfg.SmearBatches,
+		hooks:         cfg.Hooks,
+		horizon:       r.horizon,
+		warnAt:        warnAt,
+		stateStore:    cfg.StateStore,
+		stateWindow:   stateWindow,
+		stateUntil:    stateUntil,
+		stateTimeout:  cfg.stateTimeout(),
+		lastTimestamp: stateUntil,
 		sequence:      0,
-		versionShift:  sequenceBits + nodeBits + timeBits,
-		timeShift:     sequenceBits + nodeBits,
-		nodeShift:     sequenceBits,
+		bootNonce:     newBootNonce(),
+		versionPrefix: layout.prefix(),
+		fieldBits:     r.fieldBits,
+		timeShift:     layout.timeShift(),
+		nodeShift:     layout.nodeShift(),
+		seqShift:      layout.sequenceShift(),
 	}
 
+	if cfg.Layout == nil {
+		markUsed(layout.Version)
+	}
 	return g, nil
 }
 
 // NextID generates the next unique ID
 func (g *Generator) NextID() (uint64, error) {
+	return g.NextIDTagged("")
+}
+
+// NextIDTagged generates the next unique ID on behalf of the caller
+// identified by tag. The tag is charged against its quota and recorded in
+// the audit log, if either is configured.
+func (g *Generator) NextIDTagged(tag string) (uint64, error) {
+	if g.quotas != nil && !g.quotas.Allow(tag) {
+		return 0, ErrQuotaExceeded
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	id, err := g.nextID()
+	if err != nil {
+		if g.quotas != nil {
+			g.quotas.releaseN(tag, 1)
+		}
+		return 0, err
+	}
+
+	g.issued++
+	if g.audit != nil {
+		g.audit.Record(id, g.lastRead, tag)
+	}
+
+	return id, nil
+}
+
+// nextIDWithField generates the next ID with the named custom field set to
+// value for this call only
+func (g *Generator) nextIDWithField(name string, value uint64) (uint64, error) {
+	shift, bits, ok := g.layout.field(name)
+	if !ok {
+		return 0, fmt.Errorf("%w: version %d has no %s field", ErrInvalidLayout, g.layout.Version, name)
+	}
+	if value > mask(bits) {
+		return 0, fmt.Errorf("%w: %s %d (max: %d)", ErrComponentRange, name, value, mask(bits))
+	}
+
+	if g.quotas != nil && !g.quotas.Allow("") {
+		return 0, ErrQuotaExceeded
+	}
+
 	g.mu.Lock()
 	defer g.mu.Unlock()
 
-	timestamp := g.currentTimestamp()
+	id, err := g.nextID()
+	if err != nil {
+		if g.quotas != nil {
+			g.quotas.releaseN("", 1)
+		}
+		return 0, err
+	}
+	id = id&^(mask(bits)<<shift) | value<<shift
 
-	if timestamp > g.layout.MaxTimestamp {
-		return 0, errors.New("timestamp overflow for version")
+	g.issued++
+	if g.audit != nil {
+		g.audit.Record(id, g.lastRead, "")
 	}
 
+	return id, nil
+}
+
+// nextID generates the next ID. The caller must hold g.mu and account for
+// the ID as issued.
+func (g *Generator) nextID() (uint64, error) {
+	now, err := g.clock.Now()
+	if err != nil {
+		return 0, err
+	}
+	timestamp, err := g.usableTimestampAt(now)
+	if err != nil {
+		return 0, err
+	}
+	g.lastRead = now
+
 	// Handle clock rollback
 	if timestamp < g.lastTimestamp {
+		start := time.Now()
 		for timestamp < g.lastTimestamp {
-			time.Sleep(100 * time.Microsecond)
-			timestamp = g.currentTimestamp()
+			g.pause(g.lastTimestamp)
+			if timestamp, err = g.currentTimestamp(); err != nil {
+				return 0, err
+			}
+		}
+		if g.hooks.OnRollbackWait != nil {
+			g.hooks.OnRollbackWait(time.Since(start))
 		}
 	}
 
@@ -138,7 +685,13 @@ func (g *Generator) NextID() (uint64, error) {
 
 		// Sequence overflow - wait for next millisecond
 		if g.sequence == 0 {
-			timestamp = g.waitNextTimestamp(timestamp)
+			start := time.Now()
+			if timestamp, err = g.waitNextTimestamp(timestamp); err != nil {
+				return 0, err
+			}
+			if g.hooks.OnOverflowWait != nil {
+				g.hooks.OnOverflowWait(time.Since(start))
+			}
 		}
 	} else {
 		// New millisecond - reset sequence
@@ -147,53 +700,147 @@ func (g *Generator) NextID() (uint64, error) {
 
 	g.lastTimestamp = timestamp
 
-	// Encode ID: [version][timestamp][nodeID][sequence]
-	id := (uint64(g.layout.Version) << g.versionShift) |
+	if g.stateStore != nil && timestamp >= g.stateUntil {
+		if err := g.saveState(timestamp); err != nil {
+			return 0, err
+		}
+	}
+
+	if g.warnAt != 0 && timestamp >= g.warnAt {
+		g.warnAt = 0
+		g.hooks.OnLifetimeWarning(float64(timestamp)/float64(g.layout.MaxTimestamp), g.layout.ExhaustionTime())
+	}
+
+	return g.encode(timestamp, g.sequence), nil
+}
+
+// encode packs an ID: [version][timestamp][fields][nodeID][sequence]
+func (g *Generator) encode(timestamp, sequence uint64) uint64 {
+	return g.versionPrefix |
 		(timestamp << g.timeShift) |
+		g.fieldBits |
 		(g.nodeID << g.nodeShift) |
-		g.sequence
-
-	return id, nil
+		(sequence << g.seqShift)
 }
 
+// Decode decodes an ID using the registered layout matching its version
 func Decode(id uint64) (*DecodedID, error) {
-	version, layout := extractVersion(id)
-	if layout == nil {
-		return nil, ErrInvalidVersion
+	layout, err := extractVersion(id)
+	if err != nil {
+		return nil, err
 	}
+	return layout.decode(id), nil
+}
 
-	timeShift := layout.SequenceBits + layout.NodeBits
-	nodeShift := layout.SequenceBits
+// DecodeWithLayout decodes an ID using an explicitly chosen layout. It is
+// required for versionless layouts, whose IDs carry no version to dispatch on.
+func DecodeWithLayout(id uint64, layout *VersionLayout) (*DecodedID, error) {
+	if err := layout.validate(); err != nil {
+		return nil, err
+	}
+	if layout.VersionBits > 0 && !layout.matches(id) {
+		return nil, fmt.Errorf("%w: ID does not carry version %d", ErrInvalidVersion, layout.Version)
+	}
+	return layout.decode(id), nil
+}
 
-	timestamp := (id >> timeShift) & layout.MaxTimestamp
-	nodeID := (id >> nodeShift) & layout.MaxNodeID
-	sequence := id & layout.MaxSequence
+// BootNonce returns the random nonce chosen when the generator was created.
+// It distinguishes IDs issued by different incarnations of the same node ID.
+func (g *Generator) BootNonce() uint64 {
+	return g.bootNonce
+}
 
-	actualTime := layout.Epoch.Add(time.Duration(timestamp) * layout.TimeUnit)
+// usableTimestamp reads the clock and returns the current timestamp,
+// failing if the generator may not issue IDs at it
+func (g *Generator) usableTimestamp() (uint64, error) {
+	now, err := g.clock.Now()
+	if err != nil {
+		return 0, err
+	}
+	return g.usableTimestampAt(now)
+}
 
-	return &DecodedID{
-		Version:   version,
-		Timestamp: timestamp,
-		NodeID:    nodeID,
-		Sequence:  sequence,
-		Time:      actualTime,
-	}, nil
+// usableTimestampAt is usableTimestamp for the clock reading now
+func (g *Generator) usableTimestampAt(now time.Time) (uint64, error) {
+	if !g.notBefore.IsZero() && now.Before(g.notBefore) {
+		return 0, ErrClockBeforeFloor
+	}
+
+	timestamp := g.timestampAt(now)
+
+	if timestamp > g.layout.MaxTimestamp {
+		// Before the epoch, the elapsed time wraps around to a huge value
+		if now.Before(g.layout.Epoch) {
+			return 0, ErrEpochInFuture
+		}
+		return 0, &TimestampOverflowError{Version: g.layout.Version, Exhausted: g.layout.ExhaustionTime()}
+	}
+	if timestamp > g.horizon {
+		return 0, ErrHorizonReached
+	}
+	return timestamp, nil
+}
+
+// currentTimestamp reads the clock and returns the timestamp relative to
+// epoch, keeping the reading as lastRead. The caller must hold g.mu.
+func (g *Generator) currentTimestamp() (uint64, error) {
+	now, err := g.clock.Now()
+	if err != nil {
+		return 0, err
+	}
+	g.lastRead = now
+	return g.timestampAt(now), nil
 }
 
-// currentTimestamp returns the current timestamp relative to epoch
-func (g *Generator) currentTimestamp() uint64 {
-	elapsed := time.Since(g.layout.Epoch)
+// timestampAt returns the timestamp of t relative to epoch
+func (g *Generator) timestampAt(t time.Time) uint64 {
+	elapsed := t.Sub(g.layout.Epoch)
 	return uint64(elapsed / g.layout.TimeUnit)
 }
 
 // waitNextTimestamp waits until the next millisecond
-func (g *Generator) waitNextTimestamp(lastTimestamp uint64) uint64 {
-	timestamp := g.currentTimestamp()
-	for timestamp <= lastTimestamp {
-		time.Sleep(100 * time.Microsecond)
-		timestamp = g.currentTimestamp()
+func (g *Generator) waitNextTimestamp(lastTimestamp uint64) (uint64, error) {
+	timestamp, err := g.currentTimestamp()
+	for err == nil && timestamp <= lastTimestamp {
+		g.pause(lastTimestamp + 1)
+		timestamp, err = g.currentTimestamp()
 	}
-	return timestamp
+	return timestamp, err
+}
+
+// pause sleeps while waiting for the clock to reach target, according to
+// the configured wait strategy
+func (g *Generator) pause(target uint64) {
+	if g.waitStrategy == WaitSleep {
+		if now, err := g.clock.Now(); err == nil {
+			if d := g.layout.timeOf(target).Sub(now); d > 0 {
+				time.Sleep(d)
+				return
+			}
+		}
+	}
+	time.Sleep(100 * time.Microsecond)
+}
+
+// timeOf converts a timestamp to wall time. The multiplication is done in
+// 128 bits since timestamps more than ~292 years past the epoch overflow
+// time.Duration.
+func (l *VersionLayout) timeOf(timestamp uint64) time.Time {
+	hi, lo := bits.Mul64(timestamp, uint64(l.TimeUnit))
+	sec, nsec := bits.Div64(hi, lo, uint64(time.Second))
+	return time.Unix(l.Epoch.Unix()+int64(sec), int64(l.Epoch.Nanosecond())+int64(nsec)).In(l.Epoch.Location())
+}
+
+// ExhaustionTime returns the first instant the timestamp field cannot
+// hold, after which generators fail with ErrTimestampOverflow
+func (l *VersionLayout) ExhaustionTime() time.Time {
+	return l.timeOf(l.MaxTimestamp).Add(l.TimeUnit)
+}
+
+// Field returns the value of the named custom field
+func (d *DecodedID) Field(name string) (uint64, bool) {
+	value, ok := d.Fields[name]
+	return value, ok
 }
 
 // String returns a formatted representation of the decoded ID
@@ -202,8 +849,24 @@ func (d *DecodedID) String() string {
 		d.Version, d.Time.Format(time.RFC3339Nano), d.NodeID, d.Sequence)
 }
 
-func extractVersion(id uint64) (Version, *VersionLayout) {
-	v := Version((id >> 61) & 0x07)
-	layout := versionLayouts[v]
-	return v, layout
+// newBootNonce returns a random, non-zero nonce for a generator incarnation
+func newBootNonce() uint64 {
+	var b [8]byte
+	for {
+		rand.Read(b[:])
+		if n := binary.BigEndian.Uint64(b[:]); n != 0 {
+			return n
+		}
+	}
+}
+
+// extractVersion returns the registered layout, builtin or registered at
+// runtime, whose version field matches the top bits of id
+func extractVersion(id uint64) (*VersionLayout, error) {
+	for _, layout := range decodeTable.Load()[id>>61] {
+		if layout.matches(id) {
+			return layout, nil
+		}
+	}
+	return nil, &UnknownVersionError{Value: Version(id >> 61)}
 }
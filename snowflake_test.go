@@ -1,6 +1,7 @@
 package snowflake
 
 import (
+	"errors"
 	"log"
 	"sync"
 	"testing"
@@ -28,6 +29,16 @@ func TestNewGenerator(t *testing.T) {
 			config:  Config{Version: Version0, NodeID: 256},
 			wantErr: true,
 		},
+		{
+			name:    "clock after floor",
+			config:  Config{Version: Version0, NodeID: 1, NotBefore: time.Now().Add(-time.Hour)},
+			wantErr: false,
+		},
+		{
+			name:    "clock before floor",
+			config:  Config{Version: Version0, NodeID: 1, NotBefore: time.Now().Add(24 * time.Hour)},
+			wantErr: true,
+		},
 		{
 			name:    "invalid version",
 			config:  Config{Version: 99, NodeID: 100},
@@ -49,6 +60,54 @@ func TestNewGenerator(t *testing.T) {
 	}
 }
 
+// withTestLayout registers layout for the duration of the test, bypassing
+// the version number policy
+func withTestLayout(t *testing.T, layout *VersionLayout) {
+	t.Helper()
+	if err := registerLayout(layout); err != nil {
+		t.Fatalf("Failed to register test layout: %v", err)
+	}
+	t.Cleanup(func() {
+		registryMu.Lock()
+		defer registryMu.Unlock()
+		delete(versionLayouts, layout.Version)
+		rebuildDecodeTable()
+		delete(usedVersions, layout.Version)
+	})
+}
+
+func TestNewGenerator_PreEpoch(t *testing.T) {
+	future := *versionLayouts[Version0]
+	future.Version = 6
+	future.Epoch = time.Now().Add(24 * time.Hour)
+	withTestLayout(t, &future)
+
+	_, err := NewGenerator(Config{Version: 6, NodeID: 1})
+	if !errors.Is(err, ErrEpochInFuture) {
+		t.Fatalf("Expected ErrEpochInFuture, got %v", err)
+	}
+
+	var warned bool
+	gen, err := NewGenerator(Config{
+		Version:       6,
+		NodeID:        1,
+		AllowPreEpoch: true,
+		Hooks: Hooks{
+			OnPreEpoch: func(now, epoch time.Time) { warned = true },
+		},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create pre-epoch generator: %v", err)
+	}
+	if !warned {
+		t.Error("Expected OnPreEpoch hook to be called")
+	}
+
+	if _, err := gen.NextID(); !errors.Is(err, ErrEpochInFuture) {
+		t.Errorf("Expected ErrEpochInFuture from NextID, got %v", err)
+	}
+}
+
 func TestNextID_UniqueIDs(t *testing.T) {
 	gen, err := NewGenerator(Config{Version: Version0, NodeID: 1})
 	if err != nil {
@@ -170,6 +229,10 @@ func TestDecode(t *testing.T) {
 		t.Errorf("Expected node ID 42, got %d", decoded.NodeID)
 	}
 
+	if decoded.Fields != nil {
+		t.Errorf("Expected no custom fields for built-in layout, got %v", decoded.Fields)
+	}
+
 	// Verify timestamp is reasonable (within last second)
 	now := time.Now()
 	timeDiff := now.Sub(decoded.Time)
@@ -178,6 +241,29 @@ func TestDecode(t *testing.T) {
 	}
 }
 
+func TestDecode_UnknownVersion(t *testing.T) {
+	id := uint64(6)<<61 | 12345
+
+	_, err := Decode(id)
+	var unknown *UnknownVersionError
+	if !errors.As(err, &unknown) || unknown.Value != 6 || !errors.Is(err, ErrInvalidVersion) {
+		t.Fatalf("Expected UnknownVersionError for version 6, got %v", err)
+	}
+
+	// Layouts registered at runtime are dispatched to like builtins
+	custom := *versionLayouts[Version0]
+	custom.Version = 6
+	withTestLayout(t, &custom)
+
+	decoded, err := Decode(id)
+	if err != nil {
+		t.Fatalf("Decode failed after registration: %v", err)
+	}
+	if decoded.Version != 6 || decoded.Sequence != 12345&0xFF {
+		t.Errorf("Unexpected decode: %s", decoded)
+	}
+}
+
 func TestDecode_MultipleIDs(t *testing.T) {
 	gen, err := NewGenerator(Config{Version: Version0, NodeID: 100})
 	if err != nil {
@@ -250,6 +336,27 @@ func TestSequenceOverflow(t *testing.T) {
 	}
 }
 
+func TestSequenceOverflow_WaitSleep(t *testing.T) {
+	gen, err := NewGenerator(Config{Version: Version0, NodeID: 1, WaitStrategy: WaitSleep})
+	if err != nil {
+		t.Fatalf("Failed to create generator: %v", err)
+	}
+
+	const numIDs = 3000
+	var lastID uint64
+
+	for i := 0; i < numIDs; i++ {
+		id, err := gen.NextID()
+		if err != nil {
+			t.Fatalf("Failed to generate ID at index %d: %v", i, err)
+		}
+		if i > 0 && id <= lastID {
+			t.Fatalf("IDs not monotonically increasing: %d <= %d", id, lastID)
+		}
+		lastID = id
+	}
+}
+
 func TestMultipleGenerators_DifferentNodes(t *testing.T) {
 	gen1, err := NewGenerator(Config{Version: Version0, NodeID: 1})
 	if err != nil {
@@ -310,6 +417,230 @@ func TestVersionEncoding(t *testing.T) {
 	}
 }
 
+func TestConfig_Validate(t *testing.T) {
+	if err := (Config{Version: Version0, NodeID: 255}).Validate(); err != nil {
+		t.Errorf("Expected valid config, got %v", err)
+	}
+
+	tests := []struct {
+		name string
+		cfg  Config
+		want error
+	}{
+		{"unknown version", Config{Version: 99}, ErrInvalidVersion},
+		{"node out of range", Config{Version: Version0, NodeID: 256}, ErrInvalidNodeID},
+		{"clock before floor", Config{Version: Version0, NotBefore: time.Now().Add(time.Hour)}, ErrClockBeforeFloor},
+		{"unknown field", Config{Version: Version0, Fields: map[string]uint64{"region": 1}}, ErrInvalidLayout},
+		{"unknown source", Config{Version: Version0, Source: "nowhere"}, ErrUnknownSource},
+		{"builtin split", Config{Version: Version0, NodeBits: 10}, ErrReservedVersion},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.cfg.Validate(); !errors.Is(err, tt.want) {
+				t.Errorf("Expected %v, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestConfig_ValidateDoesNotRegister(t *testing.T) {
+	var called bool
+	future := *versionLayouts[Version0]
+	future.Version = 6
+	future.Epoch = time.Now().Add(time.Hour)
+	withTestLayout(t, &future)
+
+	cfg := Config{Version: 6, AllowPreEpoch: true, Hooks: Hooks{OnPreEpoch: func(now, epoch time.Time) { called = true }}}
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("Validate failed: %v", err)
+	}
+	if called {
+		t.Error("Validate should not call hooks")
+	}
+
+	if err := (Config{Version: 7, NodeBits: 10, SequenceBits: 10}).Validate(); err != nil {
+		t.Fatalf("Validate failed for a bit split: %v", err)
+	}
+	if _, ok := LookupLayout(7); ok {
+		t.Error("Validate should not register bit splits")
+	}
+}
+
+func TestNewGenerator_PositiveInt64(t *testing.T) {
+	for _, v := range []Version{Version0, Version2, Version3} {
+		gen, err := NewGenerator(Config{Version: v, PositiveInt64: true})
+		if err != nil {
+			t.Fatalf("Version %d: expected positive IDs, got %v", v, err)
+		}
+		if id, _ := gen.NextID(); int64(id) <= 0 {
+			t.Errorf("Version %d: expected a positive int64, got %d", v, int64(id))
+		}
+	}
+
+	high := *versionLayouts[Version0]
+	high.Version = 6
+	withTestLayout(t, &high)
+	if _, err := NewGenerator(Config{Version: 6, PositiveInt64: true}); !errors.Is(err, ErrInvalidLayout) {
+		t.Errorf("Expected ErrInvalidLayout for version 0b110, got %v", err)
+	}
+
+	full, err := NewLayoutBuilder().Time(46, time.Millisecond, EpochY2026).Field("node", 8).Sequence(10).Build()
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+	if _, err := NewGenerator(Config{Layout: full, PositiveInt64: true}); !errors.Is(err, ErrInvalidLayout) {
+		t.Errorf("Expected ErrInvalidLayout for a 64-bit versionless layout, got %v", err)
+	}
+}
+
+func TestNextID_TimestampOverflow(t *testing.T) {
+	layout := TwitterLayout()
+	exhausted := layout.ExhaustionTime()
+
+	now := exhausted.Add(-time.Millisecond)
+	clock := ClockFunc(func() (time.Time, error) { return now, nil })
+	gen, err := NewGenerator(Config{Layout: layout, Clock: clock})
+	if err != nil {
+		t.Fatalf("Failed to create generator: %v", err)
+	}
+	if _, err := gen.NextID(); err != nil {
+		t.Fatalf("Expected an ID in the last millisecond, got %v", err)
+	}
+
+	now = exhausted
+	_, err = gen.NextID()
+	if !errors.Is(err, ErrTimestampOverflow) {
+		t.Fatalf("Expected ErrTimestampOverflow, got %v", err)
+	}
+	var overflow *TimestampOverflowError
+	if !errors.As(err, &overflow) || overflow.Version != layout.Version || !overflow.Exhausted.Equal(exhausted) {
+		t.Errorf("Unexpected overflow error: %#v", err)
+	}
+}
+
+func TestNewGenerator_ImpossibleEpoch(t *testing.T) {
+	exhausted := TwitterLayout().ExhaustionTime()
+
+	_, err := NewGenerator(Config{Layout: TwitterLayout(), Clock: fixedClock(exhausted)})
+	var overflow *TimestampOverflowError
+	if !errors.As(err, &overflow) || !overflow.Exhausted.Equal(exhausted) {
+		t.Errorf("Expected TimestampOverflowError for an exhausted layout, got %v", err)
+	}
+
+	// Elapsed time since year 1 saturates time.Duration
+	ancient := &VersionLayout{
+		TimeBits:     54,
+		SequenceBits: 10,
+		TimeUnit:     time.Millisecond,
+		Epoch:        time.Date(1, 1, 1, 0, 0, 0, 0, time.UTC),
+		MaxTimestamp: mask(54),
+		MaxSequence:  mask(10),
+	}
+	if _, err := NewGenerator(Config{Layout: ancient}); !errors.Is(err, ErrInvalidLayout) {
+		t.Errorf("Expected ErrInvalidLayout for an ancient epoch, got %v", err)
+	}
+}
+
+func TestTwitterLayout(t *testing.T) {
+	layout := TwitterLayout()
+	if _, err := ParseVersion("twitter"); !errors.Is(err, ErrInvalidVersion) {
+		t.Errorf("Expected the Twitter layout not to be registered, got %v", err)
+	}
+	if _, ok := LookupLayout(1); ok {
+		t.Error("Expected builtin version 1 to stay free")
+	}
+
+	// Example ID from Twitter's API documentation, created
+	// Wed Oct 10 20:19:24 +0000 2018
+	decoded, err := DecodeWithLayout(1050118621198921728, layout)
+	if err != nil {
+		t.Fatalf("Failed to decode Twitter ID: %v", err)
+	}
+	want := time.Date(2018, 10, 10, 20, 19, 24, 211_000_000, time.UTC)
+	if !decoded.Time.Equal(want) || decoded.NodeID != 347 || decoded.Sequence != 0 {
+		t.Errorf("Unexpected decode: %s", decoded)
+	}
+
+	gen, err := NewGenerator(Config{Layout: layout, NodeID: 1023})
+	if err != nil {
+		t.Fatalf("Failed to create generator: %v", err)
+	}
+	id, err := gen.NextID()
+	if err != nil {
+		t.Fatalf("Failed to generate ID: %v", err)
+	}
+	if id>>63 != 0 {
+		t.Errorf("Expected sign bit zero, got %d", id)
+	}
+	if decoded, _ := DecodeWithLayout(id, layout); decoded.NodeID != 1023 {
+		t.Errorf("Expected worker 1023, got %s", decoded)
+	}
+}
+
+func TestVersion2_Micro(t *testing.T) {
+	if v, err := ParseVersion("micro"); err != nil || v != Version2 {
+		t.Errorf("Expected micro to name Version2, got %v (%v)", v, err)
+	}
+
+	gen, err := NewGenerator(Config{Version: Version2, NodeID: 127})
+	if err != nil {
+		t.Fatalf("Failed to create generator: %v", err)
+	}
+	ids, err := gen.NextIDs(100)
+	if err != nil {
+		t.Fatalf("Failed to generate IDs: %v", err)
+	}
+
+	first, err := Decode(ids[0])
+	if err != nil {
+		t.Fatalf("Failed to decode: %v", err)
+	}
+	last, _ := Decode(ids[len(ids)-1])
+	if first.Version != Version2 || first.NodeID != 127 {
+		t.Errorf("Unexpected decode: %s", first)
+	}
+	if diff := time.Since(first.Time); diff < 0 || diff > time.Second {
+		t.Errorf("Decoded time seems incorrect: %v (diff: %v)", first.Time, diff)
+	}
+
+	// 100 IDs need at least 7 microsecond ticks at 16 per tick
+	if span := last.Timestamp - first.Timestamp; span < 6 {
+		t.Errorf("Expected IDs to span microsecond ticks, got %d", span)
+	}
+	if last.Time.Sub(first.Time) != time.Duration(last.Timestamp-first.Timestamp)*time.Microsecond {
+		t.Errorf("Expected microsecond time resolution: %s .. %s", first, last)
+	}
+}
+
+func TestVersion3_Seconds(t *testing.T) {
+	if v, err := ParseVersion("seconds"); err != nil || v != Version3 {
+		t.Errorf("Expected seconds to name Version3, got %v (%v)", v, err)
+	}
+
+	gen, err := NewGenerator(Config{Version: Version3, NodeID: 65535})
+	if err != nil {
+		t.Fatalf("Failed to create generator: %v", err)
+	}
+	id, err := gen.NextID()
+	if err != nil {
+		t.Fatalf("Failed to generate ID: %v", err)
+	}
+
+	decoded, err := Decode(id)
+	if err != nil {
+		t.Fatalf("Failed to decode: %v", err)
+	}
+	if decoded.Version != Version3 || decoded.NodeID != 65535 {
+		t.Errorf("Unexpected decode: %s", decoded)
+	}
+	if decoded.Time.Nanosecond() != 0 {
+		t.Errorf("Expected whole-second time, got %v", decoded.Time)
+	}
+	if diff := time.Since(decoded.Time); diff < 0 || diff > 2*time.Second {
+		t.Errorf("Decoded time seems incorrect: %v (diff: %v)", decoded.Time, diff)
+	}
+}
+
 // Benchmark tests
 func BenchmarkNextID(b *testing.B) {
 	gen, err := NewGenerator(Config{Version: Version0, NodeID: 1})
@@ -0,0 +1,82 @@
+package snowflake
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// SourceField is the name of the optional custom field recording which
+// system minted an ID (live traffic, a backfill, a migration, ...). Add it
+// to a layout with LayoutBuilder.Field(SourceField, bits), using 2–4 bits,
+// and select the source with Config.Source.
+const SourceField = "source"
+
+// Source field widths accepted by layout validation
+const (
+	minSourceBits = 2
+	maxSourceBits = 4
+)
+
+var (
+	ErrUnknownSource = errors.New("unknown source")
+	ErrSourceTaken   = errors.New("source already registered")
+)
+
+var (
+	sourceMu     sync.RWMutex
+	sourceLabels = map[uint64]string{}
+	sourceValues = map[string]uint64{}
+)
+
+// RegisterSource maps a source field value to a label, e.g. 1 to
+// "backfill". Mappings are global and should be registered at init time,
+// identically in every process that mints or decodes IDs. Values and
+// labels must each be registered once.
+func RegisterSource(value uint64, label string) error {
+	if label == "" || value > mask(maxSourceBits) {
+		return fmt.Errorf("%w: value %d label %q", ErrUnknownSource, value, label)
+	}
+
+	sourceMu.Lock()
+	defer sourceMu.Unlock()
+
+	if existing, ok := sourceLabels[value]; ok {
+		return fmt.Errorf("%w: source value %d is %q", ErrSourceTaken, value, existing)
+	}
+	if existing, ok := sourceValues[label]; ok {
+		return fmt.Errorf("%w: source %q has value %d", ErrSourceTaken, label, existing)
+	}
+	sourceLabels[value] = label
+	sourceValues[label] = value
+	return nil
+}
+
+// SourceLabel returns the label registered for a source field value
+func SourceLabel(value uint64) (string, bool) {
+	sourceMu.RLock()
+	defer sourceMu.RUnlock()
+
+	label, ok := sourceLabels[value]
+	return label, ok
+}
+
+// sourceValue returns the value registered for a source label
+func sourceValue(label string) (uint64, bool) {
+	sourceMu.RLock()
+	defer sourceMu.RUnlock()
+
+	value, ok := sourceValues[label]
+	return value, ok
+}
+
+// Source returns the registered label of the ID's source field. It
+// reports false if the layout has no source field or the value has no
+// registered label.
+func (d *DecodedID) Source() (string, bool) {
+	value, ok := d.Field(SourceField)
+	if !ok {
+		return "", false
+	}
+	return SourceLabel(value)
+}
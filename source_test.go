@@ -0,0 +1,96 @@
+package snowflake
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// withTestSource registers a source label for the duration of the test
+func withTestSource(t *testing.T, value uint64, label string) {
+	t.Helper()
+	if err := RegisterSource(value, label); err != nil {
+		t.Fatalf("Failed to register source: %v", err)
+	}
+	t.Cleanup(func() {
+		sourceMu.Lock()
+		defer sourceMu.Unlock()
+		delete(sourceLabels, value)
+		delete(sourceValues, label)
+	})
+}
+
+func TestSource(t *testing.T) {
+	withTestSource(t, 0, "live")
+	withTestSource(t, 2, "backfill")
+
+	layout, err := NewLayoutBuilder().
+		Version(6, 3).
+		Time(41, time.Millisecond, EpochY2026).
+		Field(SourceField, 2).
+		Field("node", 8).
+		Sequence(10).
+		Build()
+	if err != nil {
+		t.Fatalf("Failed to build layout: %v", err)
+	}
+	withTestLayout(t, layout)
+
+	gen, err := NewGenerator(Config{Version: 6, NodeID: 1, Source: "backfill"})
+	if err != nil {
+		t.Fatalf("Failed to create generator: %v", err)
+	}
+	id, err := gen.NextID()
+	if err != nil {
+		t.Fatalf("Failed to generate ID: %v", err)
+	}
+
+	decoded, err := Decode(id)
+	if err != nil {
+		t.Fatalf("Failed to decode: %v", err)
+	}
+	if label, ok := decoded.Source(); !ok || label != "backfill" {
+		t.Errorf("Expected source backfill, got %q (%t)", label, ok)
+	}
+
+	if _, err := NewGenerator(Config{Version: 6, Source: "migration"}); !errors.Is(err, ErrUnknownSource) {
+		t.Errorf("Expected ErrUnknownSource, got %v", err)
+	}
+	if _, err := NewGenerator(Config{Version: Version0, Source: "live"}); !errors.Is(err, ErrInvalidLayout) {
+		t.Errorf("Expected ErrInvalidLayout for layout without source field, got %v", err)
+	}
+
+	v0, _ := NewGenerator(Config{Version: Version0})
+	id, _ = v0.NextID()
+	decoded, _ = Decode(id)
+	if _, ok := decoded.Source(); ok {
+		t.Error("Expected no source for layout without source field")
+	}
+}
+
+func TestRegisterSource_Errors(t *testing.T) {
+	withTestSource(t, 1, "migration")
+
+	if err := RegisterSource(1, "other"); !errors.Is(err, ErrSourceTaken) {
+		t.Errorf("Expected ErrSourceTaken for duplicate value, got %v", err)
+	}
+	if err := RegisterSource(3, "migration"); !errors.Is(err, ErrSourceTaken) {
+		t.Errorf("Expected ErrSourceTaken for duplicate label, got %v", err)
+	}
+	if err := RegisterSource(16, "wide"); !errors.Is(err, ErrUnknownSource) {
+		t.Errorf("Expected error for value wider than 4 bits, got %v", err)
+	}
+}
+
+func TestValidate_SourceFieldWidth(t *testing.T) {
+	_, err := NewLayoutBuilder().
+		Version(6, 3).
+		Time(40, time.Millisecond, EpochY2026).
+		Field(SourceField, 5).
+		Field("node", 6).
+		Sequence(10).
+		Build()
+	if !errors.Is(err, ErrInvalidLayout) {
+		t.Errorf("Expected ErrInvalidLayout for 5-bit source field, got %v", err)
+	}
+}
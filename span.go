@@ -0,0 +1,63 @@
+package snowflake
+
+import "fmt"
+
+// NextIDWithSpan reserves k consecutive sequence values in a single time
+// unit and returns the first ID, so a parent event and its k-1 sub-events
+// get adjacent IDs that sort together. The IDs are base+i<<s for i in
+// [0, k), where s is Shifts().Sequence; it is zero unless the layout is
+// NodeLast, making the span base, base+1, ..., base+k-1.
+//
+// If the current time unit has fewer than k sequence values left, the
+// call waits for the next one and the remainder is skipped. k may not
+// exceed the layout's sequence capacity (MaxSequence+1).
+func (g *Generator) NextIDWithSpan(k int) (uint64, error) {
+	if k < 1 || uint64(k)-1 > g.layout.MaxSequence {
+		return 0, fmt.Errorf("%w: span %d must be 1 to %d", ErrComponentRange, k, g.layout.MaxSequence+1)
+	}
+
+	if g.quotas != nil && !g.quotas.allowN("", uint64(k)) {
+		return 0, ErrQuotaExceeded
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	base, err := g.nextSpan(uint64(k))
+	if err != nil {
+		if g.quotas != nil {
+			g.quotas.releaseN("", uint64(k))
+		}
+		return 0, err
+	}
+
+	g.issued += uint64(k)
+	if g.audit != nil {
+		for i := range uint64(k) {
+			g.audit.Record(base+i<<g.seqShift, g.lastRead, "")
+		}
+	}
+
+	return base, nil
+}
+
+// nextSpan claims k sequence values in one time unit and returns the ID of
+// the first. The caller must hold g.mu.
+func (g *Generator) nextSpan(k uint64) (uint64, error) {
+	base, err := g.nextID()
+	if err != nil {
+		return 0, err
+	}
+
+	if g.sequence+k-1 > g.layout.MaxSequence {
+		// Skip the rest of the unit; nextID then waits for the next one as
+		// on sequence overflow, saving state and checking the lifetime
+		g.sequence = g.layout.MaxSequence
+		if base, err = g.nextID(); err != nil {
+			return 0, err
+		}
+	}
+	g.sequence += k - 1
+
+	return base, nil
+}
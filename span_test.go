@@ -0,0 +1,129 @@
+package snowflake
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestNextIDWithSpan(t *testing.T) {
+	var mu sync.Mutex
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	clock := ClockFunc(func() (time.Time, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		return now, nil
+	})
+	gen, err := NewGenerator(Config{Version: Version0, NodeID: 3, Clock: clock})
+	if err != nil {
+		t.Fatalf("Failed to create generator: %v", err)
+	}
+
+	base, err := gen.NextIDWithSpan(100)
+	if err != nil {
+		t.Fatalf("NextIDWithSpan failed: %v", err)
+	}
+	first, _ := Decode(base)
+	next, err := gen.NextID()
+	if err != nil {
+		t.Fatalf("NextID failed: %v", err)
+	}
+	if next != base+100 {
+		t.Errorf("Expected next ID %d right after the span, got %d", base+100, next)
+	}
+
+	// 155 sequence values remain in this millisecond; a span of 200 moves on
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		mu.Lock()
+		now = now.Add(time.Millisecond)
+		mu.Unlock()
+	}()
+	base, err = gen.NextIDWithSpan(200)
+	if err != nil {
+		t.Fatalf("NextIDWithSpan failed: %v", err)
+	}
+	decoded, _ := Decode(base)
+	if decoded.Sequence != 0 || decoded.Timestamp != first.Timestamp+1 {
+		t.Errorf("Expected span to start a new millisecond, got %s", decoded)
+	}
+	last, _ := Decode(base + 199)
+	if last.Sequence != 199 || last.Timestamp != decoded.Timestamp || last.NodeID != 3 {
+		t.Errorf("Expected span to end at sequence 199 in the same millisecond, got %s", last)
+	}
+
+	if stats := gen.Stats(); stats.Issued != 301 {
+		t.Errorf("Expected 301 issued IDs, got %d", stats.Issued)
+	}
+	for _, k := range []int{0, 257} {
+		if _, err := gen.NextIDWithSpan(k); !errors.Is(err, ErrComponentRange) {
+			t.Errorf("Expected ErrComponentRange for span %d, got %v", k, err)
+		}
+	}
+}
+
+func TestNextIDWithSpan_Rollover(t *testing.T) {
+	layout := TwitterLayout()
+	warnAt := uint64(float64(layout.MaxTimestamp) * 0.6)
+
+	var mu sync.Mutex
+	now := layout.timeOf(warnAt - 1)
+	clock := ClockFunc(func() (time.Time, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		return now, nil
+	})
+	var warnings int
+	gen, err := NewGenerator(Config{
+		Layout:          layout,
+		Clock:           clock,
+		LifetimeWarning: 0.6,
+		Hooks:           Hooks{OnLifetimeWarning: func(float64, time.Time) { warnings++ }},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create generator: %v", err)
+	}
+
+	if _, err := gen.NextIDWithSpan(4000); err != nil {
+		t.Fatalf("NextIDWithSpan failed: %v", err)
+	}
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		mu.Lock()
+		now = now.Add(time.Millisecond)
+		mu.Unlock()
+	}()
+	if _, err := gen.NextIDWithSpan(200); err != nil {
+		t.Fatalf("NextIDWithSpan failed: %v", err)
+	}
+	if warnings != 1 {
+		t.Errorf("Expected the rollover to fire one lifetime warning, got %d", warnings)
+	}
+}
+
+func TestNextIDWithSpan_QuotaRefund(t *testing.T) {
+	var clockErr error
+	clock := ClockFunc(func() (time.Time, error) {
+		return time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC), clockErr
+	})
+	gen, err := NewGenerator(Config{
+		Version: Version0,
+		Clock:   clock,
+		Quotas:  NewQuotaManager(map[string]uint64{"": 10}),
+	})
+	if err != nil {
+		t.Fatalf("Failed to create generator: %v", err)
+	}
+
+	clockErr = errors.New("clock unavailable")
+	if _, err := gen.NextIDWithSpan(10); !errors.Is(err, clockErr) {
+		t.Fatalf("Expected the clock error, got %v", err)
+	}
+
+	// The failed span must not have spent the budget
+	clockErr = nil
+	if _, err := gen.NextIDWithSpan(10); err != nil {
+		t.Errorf("Expected the refunded budget to cover the span, got %v", err)
+	}
+}
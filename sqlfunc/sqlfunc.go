@@ -0,0 +1,83 @@
+// Package sqlfunc provides SQL scalar functions for decoding snowflake IDs
+// inside embedded databases:
+//
+//	snowflake_time(id)   -- RFC 3339 time the ID was issued, in UTC
+//	snowflake_decode(id) -- JSON object with the ID's components
+//
+// Register them on every new go-sqlite3 connection with a ConnectHook:
+//
+//	sql.Register("sqlite3_snowflake", &sqlite3.SQLiteDriver{
+//		ConnectHook: func(conn *sqlite3.SQLiteConn) error {
+//			return sqlfunc.Register(conn)
+//		},
+//	})
+//
+// The package has no driver dependency: Registrar has the same method set
+// as *sqlite3.SQLiteConn. Engines with other UDF APIs, such as DuckDB, can
+// wrap Time and Decode in their own function types.
+package sqlfunc
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/samarthasthan/snowflake"
+)
+
+// Registrar registers a Go function as a SQL function. It matches
+// (*sqlite3.SQLiteConn).RegisterFunc.
+type Registrar interface {
+	RegisterFunc(name string, impl any, pure bool) error
+}
+
+// Register adds snowflake_time and snowflake_decode to r
+func Register(r Registrar) error {
+	if err := r.RegisterFunc("snowflake_time", Time, true); err != nil {
+		return err
+	}
+	return r.RegisterFunc("snowflake_decode", Decode, true)
+}
+
+// Time returns the issue time of id in RFC 3339 form, which SQLite's date
+// functions accept. id is the ID's int64 bit pattern, as databases store it.
+func Time(id int64) (string, error) {
+	decoded, err := snowflake.Decode(uint64(id))
+	if err != nil {
+		return "", err
+	}
+	return decoded.Time.UTC().Format(time.RFC3339Nano), nil
+}
+
+// decodedJSON is the snowflake_decode result
+type decodedJSON struct {
+	Version   snowflake.Version `json:"version"`
+	Time      time.Time         `json:"time"`
+	Timestamp uint64            `json:"timestamp"`
+	NodeID    uint64            `json:"node_id"`
+	Sequence  uint64            `json:"sequence"`
+	Fields    map[string]uint64 `json:"fields,omitempty"`
+}
+
+// Decode returns the components of id as a JSON object, for use with
+// json_extract:
+//
+//	SELECT json_extract(snowflake_decode(id), '$.node_id') FROM events
+func Decode(id int64) (string, error) {
+	decoded, err := snowflake.Decode(uint64(id))
+	if err != nil {
+		return "", err
+	}
+
+	out, err := json.Marshal(decodedJSON{
+		Version:   decoded.Version,
+		Time:      decoded.Time.UTC(),
+		Timestamp: decoded.Timestamp,
+		NodeID:    decoded.NodeID,
+		Sequence:  decoded.Sequence,
+		Fields:    decoded.Fields,
+	})
+	if err != nil {
+		return "", err
+	}
+	return string(out), nil
+}
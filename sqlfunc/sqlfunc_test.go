@@ -0,0 +1,69 @@
+package sqlfunc
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/samarthasthan/snowflake"
+)
+
+// exampleID is the Version0 ID for node 7 at 2026-03-01T00:00:00Z,
+// sequence 1
+const exampleID = 334076313601793
+
+type fakeRegistrar map[string]any
+
+func (f fakeRegistrar) RegisterFunc(name string, impl any, pure bool) error {
+	if !pure {
+		return errors.New("expected pure function")
+	}
+	f[name] = impl
+	return nil
+}
+
+func TestRegister(t *testing.T) {
+	r := fakeRegistrar{}
+	if err := Register(r); err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+	for _, name := range []string{"snowflake_time", "snowflake_decode"} {
+		if _, ok := r[name]; !ok {
+			t.Errorf("Expected %s to be registered", name)
+		}
+	}
+}
+
+func TestTime(t *testing.T) {
+	got, err := Time(exampleID)
+	if err != nil {
+		t.Fatalf("Time failed: %v", err)
+	}
+	if got != "2026-03-01T00:00:00Z" {
+		t.Errorf("Expected 2026-03-01T00:00:00Z, got %s", got)
+	}
+
+	if _, err := Time(-1); !errors.Is(err, snowflake.ErrInvalidVersion) {
+		t.Errorf("Expected ErrInvalidVersion for an unregistered version, got %v", err)
+	}
+}
+
+func TestDecode(t *testing.T) {
+	got, err := Decode(exampleID)
+	if err != nil {
+		t.Fatalf("Decode failed: %v", err)
+	}
+
+	var out struct {
+		Version  int    `json:"version"`
+		Time     string `json:"time"`
+		NodeID   uint64 `json:"node_id"`
+		Sequence uint64 `json:"sequence"`
+	}
+	if err := json.Unmarshal([]byte(got), &out); err != nil {
+		t.Fatalf("Invalid JSON %s: %v", got, err)
+	}
+	if out.Version != 0 || out.Time != "2026-03-01T00:00:00Z" || out.NodeID != 7 || out.Sequence != 1 {
+		t.Errorf("Unexpected decode: %s", got)
+	}
+}
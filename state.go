@@ -0,0 +1,139 @@
+package snowflake
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+var (
+	ErrNoState       = errors.New("no saved generator state")
+	ErrStateMismatch = errors.New("saved state belongs to another version or node")
+)
+
+// defaultStateWindow is the StateWindow used when none is configured
+const defaultStateWindow = time.Second
+
+// defaultStateTimeout is the StateTimeout used when none is configured
+const defaultStateTimeout = time.Second
+
+// State is a generator's persisted high-water mark
+type State struct {
+	Version Version   `json:"version"`
+	NodeID  uint64    `json:"node_id"`
+	Until   time.Time `json:"until"` // no issued ID's time reaches this
+
+	// BootNonce identifies the generator incarnation that saved the state
+	// (see Generator.BootNonce)
+	BootNonce uint64 `json:"boot_nonce,omitempty"`
+}
+
+// StateStore persists generator state across restarts, so a replacement
+// process on a host with a lagging clock cannot reissue IDs. Stores need
+// only keep the last saved State; file, Redis and SQL implementations are
+// FileStateStore and the statestore package.
+type StateStore interface {
+	// Load returns the last saved state, or ErrNoState if there is none
+	Load(ctx context.Context) (State, error)
+
+	// Save durably replaces the saved state
+	Save(ctx context.Context, s State) error
+}
+
+// stateTimeout returns the configured StateTimeout or its default
+func (cfg Config) stateTimeout() time.Duration {
+	if cfg.StateTimeout <= 0 {
+		return defaultStateTimeout
+	}
+	return cfg.StateTimeout
+}
+
+// loadState reads the high-water mark for a new generator and returns the
+// timestamp it must not issue below
+func loadState(store StateStore, layout *VersionLayout, nodeID uint64, timeout time.Duration) (uint64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	s, err := store.Load(ctx)
+	if errors.Is(err, ErrNoState) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("loading state: %w", err)
+	}
+	if s.Version != layout.Version || s.NodeID != nodeID {
+		return 0, fmt.Errorf("%w: saved version %d node %d", ErrStateMismatch, s.Version, s.NodeID)
+	}
+	if !s.Until.After(layout.Epoch) {
+		return 0, nil
+	}
+	return uint64(s.Until.Sub(layout.Epoch) / layout.TimeUnit), nil
+}
+
+// saveState persists a high-water mark a state window past timestamp. The
+// caller must hold g.mu, so the save is bounded by g.stateTimeout.
+func (g *Generator) saveState(timestamp uint64) error {
+	ctx, cancel := context.WithTimeout(context.Background(), g.stateTimeout)
+	defer cancel()
+
+	until := timestamp + g.stateWindow
+	s := State{Version: g.layout.Version, NodeID: g.nodeID, Until: g.layout.timeOf(until), BootNonce: g.bootNonce}
+	if err := g.stateStore.Save(ctx, s); err != nil {
+		return fmt.Errorf("saving state: %w", err)
+	}
+	g.stateUntil = until
+	return nil
+}
+
+// FileStateStore keeps state in a JSON file, replaced atomically on save.
+// It suits hosts and containers with a persistent volume.
+type FileStateStore struct {
+	Path string
+}
+
+// Load implements StateStore
+func (f FileStateStore) Load(ctx context.Context) (State, error) {
+	data, err := os.ReadFile(f.Path)
+	if errors.Is(err, os.ErrNotExist) {
+		return State{}, ErrNoState
+	}
+	if err != nil {
+		return State{}, err
+	}
+	var s State
+	if err := json.Unmarshal(data, &s); err != nil {
+		return State{}, fmt.Errorf("%s: %w", f.Path, err)
+	}
+	return s, nil
+}
+
+// Save implements StateStore
+func (f FileStateStore) Save(ctx context.Context, s State) error {
+	data, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(f.Path), filepath.Base(f.Path)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), f.Path)
+}
@@ -0,0 +1,150 @@
+package snowflake
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+// failingStore is a StateStore whose saves fail
+type failingStore struct{}
+
+func (failingStore) Load(ctx context.Context) (State, error) { return State{}, ErrNoState }
+func (failingStore) Save(ctx context.Context, s State) error { return errClockRead }
+
+// blockingStore is a StateStore whose saves wait for their context
+type blockingStore struct{}
+
+func (blockingStore) Load(ctx context.Context) (State, error) { return State{}, ErrNoState }
+func (blockingStore) Save(ctx context.Context, s State) error {
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func TestFileStateStore(t *testing.T) {
+	store := FileStateStore{Path: filepath.Join(t.TempDir(), "state.json")}
+	if _, err := store.Load(context.Background()); !errors.Is(err, ErrNoState) {
+		t.Fatalf("Expected ErrNoState, got %v", err)
+	}
+
+	want := State{Version: Version0, NodeID: 9, Until: time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)}
+	if err := store.Save(context.Background(), want); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	got, err := store.Load(context.Background())
+	if err != nil || got.Version != want.Version || got.NodeID != want.NodeID || !got.Until.Equal(want.Until) {
+		t.Errorf("Expected %+v, got %+v (%v)", want, got, err)
+	}
+}
+
+func TestGenerator_StateStore(t *testing.T) {
+	store := FileStateStore{Path: filepath.Join(t.TempDir(), "state.json")}
+	base := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	gen, err := NewGenerator(Config{NodeID: 9, Clock: fixedClock(base), StateStore: store, StateWindow: 10 * time.Millisecond})
+	if err != nil {
+		t.Fatalf("Failed to create generator: %v", err)
+	}
+	before, err := gen.NextID()
+	if err != nil {
+		t.Fatalf("NextID failed: %v", err)
+	}
+
+	saved, err := store.Load(context.Background())
+	if err != nil || !saved.Until.Equal(base.Add(10*time.Millisecond)) {
+		t.Fatalf("Expected high-water mark 10ms ahead, got %+v (%v)", saved, err)
+	}
+
+	// A restart with the clock set back must not go below the mark
+	gen, err = NewGenerator(Config{NodeID: 9, Clock: NewStepClock(base.Add(-100*time.Millisecond), time.Millisecond), StateStore: store})
+	if err != nil {
+		t.Fatalf("Failed to create generator: %v", err)
+	}
+	after, err := gen.NextID()
+	if err != nil {
+		t.Fatalf("NextID failed: %v", err)
+	}
+	if decoded, _ := Decode(after); after <= before || decoded.Time.Before(saved.Until) {
+		t.Errorf("Expected an ID at or after %s, got %s", saved.Until, decoded)
+	}
+
+	if _, err := NewGenerator(Config{NodeID: 8, StateStore: store}); !errors.Is(err, ErrStateMismatch) {
+		t.Errorf("Expected ErrStateMismatch for another node, got %v", err)
+	}
+
+	gen, _ = NewGenerator(Config{StateStore: failingStore{}})
+	if _, err := gen.NextID(); !errors.Is(err, errClockRead) {
+		t.Errorf("Expected the save error, got %v", err)
+	}
+}
+
+func TestGenerator_StateTimeout(t *testing.T) {
+	gen, err := NewGenerator(Config{StateStore: blockingStore{}, StateTimeout: 10 * time.Millisecond})
+	if err != nil {
+		t.Fatalf("Failed to create generator: %v", err)
+	}
+	if _, err := gen.NextID(); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Expected the save to time out, got %v", err)
+	}
+}
+
+func TestGenerator_StateStoreSpan(t *testing.T) {
+	store := FileStateStore{Path: filepath.Join(t.TempDir(), "state.json")}
+	var mu sync.Mutex
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	clock := ClockFunc(func() (time.Time, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		return now, nil
+	})
+	gen, err := NewGenerator(Config{Clock: clock, StateStore: store, StateWindow: time.Millisecond})
+	if err != nil {
+		t.Fatalf("Failed to create generator: %v", err)
+	}
+
+	// A span rolling over to a new millisecond saves past its IDs
+	if _, err := gen.NextIDWithSpan(200); err != nil {
+		t.Fatalf("NextIDWithSpan failed: %v", err)
+	}
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		mu.Lock()
+		now = now.Add(time.Millisecond)
+		mu.Unlock()
+	}()
+	base, err := gen.NextIDWithSpan(200)
+	if err != nil {
+		t.Fatalf("NextIDWithSpan failed: %v", err)
+	}
+	saved, err := store.Load(context.Background())
+	if decoded, _ := Decode(base); err != nil || !saved.Until.After(decoded.Time) {
+		t.Errorf("Expected a mark past %s, got %+v (%v)", decoded.Time, saved, err)
+	}
+}
+
+func TestGenerator_StateIntegration(t *testing.T) {
+	store := FileStateStore{Path: filepath.Join(t.TempDir(), "state.json")}
+	cfg := Config{NodeID: 9, StateStore: store}
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("Validate failed with an empty store: %v", err)
+	}
+
+	gen, err := NewGenerator(cfg)
+	if err != nil {
+		t.Fatalf("Failed to create generator: %v", err)
+	}
+	if err := gen.Warmup(context.Background()); err != nil {
+		t.Fatalf("Warmup failed: %v", err)
+	}
+	saved, err := store.Load(context.Background())
+	if err != nil || saved.BootNonce != gen.BootNonce() {
+		t.Fatalf("Expected Warmup to save state with nonce %d, got %+v (%v)", gen.BootNonce(), saved, err)
+	}
+
+	if err := (Config{NodeID: 8, StateStore: store}).Validate(); !errors.Is(err, ErrStateMismatch) {
+		t.Errorf("Expected ErrStateMismatch from Validate, got %v", err)
+	}
+}
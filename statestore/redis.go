@@ -0,0 +1,57 @@
+package statestore
+
+import (
+	"context"
+
+	"github.com/samarthasthan/snowflake"
+)
+
+// RedisClient is the subset of a Redis client the store needs. Get reports
+// a missing key with ok false rather than an error. An adapter for
+// go-redis:
+//
+//	type goRedis struct{ *redis.Client }
+//
+//	func (c goRedis) Get(ctx context.Context, key string) (string, bool, error) {
+//		v, err := c.Client.Get(ctx, key).Result()
+//		if err == redis.Nil {
+//			return "", false, nil
+//		}
+//		return v, err == nil, err
+//	}
+//
+//	func (c goRedis) Set(ctx context.Context, key, value string) error {
+//		return c.Client.Set(ctx, key, value, 0).Err()
+//	}
+type RedisClient interface {
+	Get(ctx context.Context, key string) (value string, ok bool, err error)
+	Set(ctx context.Context, key, value string) error
+}
+
+// Redis keeps state as JSON in a Redis string. Saves are only as durable
+// as the server's persistence settings (AOF with fsync is recommended).
+type Redis struct {
+	Client RedisClient
+	Key    string
+}
+
+// Load implements snowflake.StateStore
+func (r *Redis) Load(ctx context.Context) (snowflake.State, error) {
+	value, ok, err := r.Client.Get(ctx, r.Key)
+	if err != nil {
+		return snowflake.State{}, err
+	}
+	if !ok {
+		return snowflake.State{}, snowflake.ErrNoState
+	}
+	return decode(r.Key, value)
+}
+
+// Save implements snowflake.StateStore
+func (r *Redis) Save(ctx context.Context, s snowflake.State) error {
+	value, err := encode(s)
+	if err != nil {
+		return err
+	}
+	return r.Client.Set(ctx, r.Key, value)
+}
@@ -0,0 +1,41 @@
+package statestore
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/samarthasthan/snowflake"
+)
+
+// mapRedis is an in-memory RedisClient
+type mapRedis map[string]string
+
+func (m mapRedis) Get(ctx context.Context, key string) (string, bool, error) {
+	v, ok := m[key]
+	return v, ok, nil
+}
+
+func (m mapRedis) Set(ctx context.Context, key, value string) error {
+	m[key] = value
+	return nil
+}
+
+func TestRedis(t *testing.T) {
+	ctx := context.Background()
+	store := &Redis{Client: mapRedis{}, Key: "node-7"}
+
+	if _, err := store.Load(ctx); !errors.Is(err, snowflake.ErrNoState) {
+		t.Fatalf("Expected ErrNoState, got %v", err)
+	}
+
+	want := snowflake.State{Version: snowflake.Version0, NodeID: 7, Until: time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)}
+	if err := store.Save(ctx, want); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	got, err := store.Load(ctx)
+	if err != nil || got.NodeID != 7 || !got.Until.Equal(want.Until) {
+		t.Errorf("Expected %+v, got %+v (%v)", want, got, err)
+	}
+}
@@ -0,0 +1,76 @@
+package statestore
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/samarthasthan/snowflake"
+)
+
+// Dialect selects the SQL flavour a SQL store speaks
+type Dialect uint8
+
+const (
+	// MySQL uses ? placeholders and INSERT ... ON DUPLICATE KEY UPDATE
+	MySQL Dialect = iota
+	// PostgreSQL uses $1-style placeholders and INSERT ... ON CONFLICT
+	PostgreSQL
+	// SQLite uses ? placeholders and INSERT ... ON CONFLICT, which needs
+	// SQLite 3.24 or later
+	SQLite
+)
+
+// SQL keeps state as JSON in a two-column table, one row per key:
+//
+//	CREATE TABLE snowflake_state (name VARCHAR(255) PRIMARY KEY, state TEXT NOT NULL)
+type SQL struct {
+	DB      *sql.DB
+	Table   string
+	Key     string
+	Dialect Dialect
+}
+
+// Load implements snowflake.StateStore
+func (s *SQL) Load(ctx context.Context) (snowflake.State, error) {
+	var value string
+	err := s.DB.QueryRowContext(ctx, s.query("SELECT state FROM %s WHERE name = %s", 1), s.Key).Scan(&value)
+	if errors.Is(err, sql.ErrNoRows) {
+		return snowflake.State{}, snowflake.ErrNoState
+	}
+	if err != nil {
+		return snowflake.State{}, err
+	}
+	return decode(s.Key, value)
+}
+
+// Save implements snowflake.StateStore. It upserts the key's row in one
+// statement, so saving an unchanged state succeeds even on MySQL, which
+// reports no affected rows for it.
+func (s *SQL) Save(ctx context.Context, state snowflake.State) error {
+	value, err := encode(state)
+	if err != nil {
+		return err
+	}
+
+	upsert := "INSERT INTO %s (name, state) VALUES (%s, %s) ON CONFLICT (name) DO UPDATE SET state = excluded.state"
+	if s.Dialect == MySQL {
+		upsert = "INSERT INTO %s (name, state) VALUES (%s, %s) ON DUPLICATE KEY UPDATE state = VALUES(state)"
+	}
+	_, err = s.DB.ExecContext(ctx, s.query(upsert, 2), s.Key, value)
+	return err
+}
+
+// query formats a statement with the table name and n placeholders
+func (s *SQL) query(format string, n int) string {
+	args := []any{s.Table}
+	for i := 1; i <= n; i++ {
+		if s.Dialect == PostgreSQL {
+			args = append(args, fmt.Sprintf("$%d", i))
+		} else {
+			args = append(args, "?")
+		}
+	}
+	return fmt.Sprintf(format, args...)
+}
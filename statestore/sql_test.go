@@ -0,0 +1,141 @@
+package statestore
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/samarthasthan/snowflake"
+)
+
+// fakeDB is a database/sql driver serving the store's two statements
+// from a map, recording the queries it sees
+type fakeDB struct {
+	mu      sync.Mutex
+	rows    map[string]string
+	queries []string
+}
+
+func (d *fakeDB) Open(name string) (driver.Conn, error) { return fakeConn{d}, nil }
+
+type fakeConn struct{ db *fakeDB }
+
+func (c fakeConn) Prepare(query string) (driver.Stmt, error) { return fakeStmt{c.db, query}, nil }
+func (c fakeConn) Close() error                              { return nil }
+func (c fakeConn) Begin() (driver.Tx, error)                 { return nil, errors.New("not supported") }
+
+type fakeStmt struct {
+	db    *fakeDB
+	query string
+}
+
+func (s fakeStmt) Close() error  { return nil }
+func (s fakeStmt) NumInput() int { return -1 }
+
+func (s fakeStmt) Exec(args []driver.Value) (driver.Result, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	s.db.queries = append(s.db.queries, s.query)
+
+	// Like MySQL, report no affected rows when the value is unchanged, and
+	// reject a plain INSERT of an existing key
+	key, value := args[0].(string), args[1].(string)
+	old, exists := s.db.rows[key]
+	if exists && !strings.Contains(s.query, " ON ") {
+		return nil, errors.New("duplicate key")
+	}
+	s.db.rows[key] = value
+	if exists && old == value {
+		return driver.RowsAffected(0), nil
+	}
+	return driver.RowsAffected(1), nil
+}
+
+func (s fakeStmt) Query(args []driver.Value) (driver.Rows, error) {
+	s.db.mu.Lock()
+	defer s.db.mu.Unlock()
+	s.db.queries = append(s.db.queries, s.query)
+
+	value, ok := s.db.rows[args[0].(string)]
+	return &fakeRows{value: value, done: !ok}, nil
+}
+
+type fakeRows struct {
+	value string
+	done  bool
+}
+
+func (r *fakeRows) Columns() []string { return []string{"state"} }
+func (r *fakeRows) Close() error      { return nil }
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if r.done {
+		return io.EOF
+	}
+	dest[0], r.done = r.value, true
+	return nil
+}
+
+func TestSQL(t *testing.T) {
+	tests := []struct {
+		name    string
+		dialect Dialect
+		want    []string
+	}{
+		{"postgres", PostgreSQL, []string{
+			"SELECT state FROM snowflake_state WHERE name = $1",
+			"INSERT INTO snowflake_state (name, state) VALUES ($1, $2) ON CONFLICT (name) DO UPDATE SET state = excluded.state",
+		}},
+		{"mysql", MySQL, []string{
+			"SELECT state FROM snowflake_state WHERE name = ?",
+			"INSERT INTO snowflake_state (name, state) VALUES (?, ?) ON DUPLICATE KEY UPDATE state = VALUES(state)",
+		}},
+		{"sqlite", SQLite, []string{
+			"SELECT state FROM snowflake_state WHERE name = ?",
+			"INSERT INTO snowflake_state (name, state) VALUES (?, ?) ON CONFLICT (name) DO UPDATE SET state = excluded.state",
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := &fakeDB{rows: map[string]string{}}
+			sql.Register("statestore_fake_"+tt.name, fake)
+			db, err := sql.Open("statestore_fake_"+tt.name, "")
+			if err != nil {
+				t.Fatalf("Failed to open database: %v", err)
+			}
+			defer db.Close()
+
+			ctx := context.Background()
+			store := &SQL{DB: db, Table: "snowflake_state", Key: "node-7", Dialect: tt.dialect}
+
+			if _, err := store.Load(ctx); !errors.Is(err, snowflake.ErrNoState) {
+				t.Fatalf("Expected ErrNoState, got %v", err)
+			}
+
+			// The repeated save leaves the row unchanged
+			until := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+			for i, offset := range []time.Duration{0, 0, time.Second} {
+				if err := store.Save(ctx, snowflake.State{NodeID: 7, Until: until.Add(offset)}); err != nil {
+					t.Fatalf("Save %d failed: %v", i, err)
+				}
+			}
+			got, err := store.Load(ctx)
+			if err != nil || !got.Until.Equal(until.Add(time.Second)) {
+				t.Errorf("Expected the last save, got %+v (%v)", got, err)
+			}
+
+			load, save := tt.want[0], tt.want[1]
+			want := []string{load, save, save, save, load}
+			if strings.Join(fake.queries, "\n") != strings.Join(want, "\n") {
+				t.Errorf("Unexpected queries:\n%s", strings.Join(fake.queries, "\n"))
+			}
+		})
+	}
+}
@@ -0,0 +1,34 @@
+// Package statestore provides snowflake.StateStore implementations backed
+// by Redis and SQL databases, for container platforms without persistent
+// volumes (snowflake.FileStateStore covers those with one). It has no
+// driver dependency: SQL uses database/sql, and Redis goes through the
+// small RedisClient interface.
+//
+// Each store keeps one generator's state under a key, so every node ID
+// needs its own key:
+//
+//	store := &statestore.SQL{DB: db, Table: "snowflake_state", Key: "node-7", Dialect: statestore.PostgreSQL}
+//	gen, err := snowflake.NewGenerator(snowflake.Config{NodeID: 7, StateStore: store})
+package statestore
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/samarthasthan/snowflake"
+)
+
+// encode serializes state for stores that keep it as text
+func encode(s snowflake.State) (string, error) {
+	data, err := json.Marshal(s)
+	return string(data), err
+}
+
+// decode parses state saved by encode
+func decode(key, value string) (snowflake.State, error) {
+	var s snowflake.State
+	if err := json.Unmarshal([]byte(value), &s); err != nil {
+		return snowflake.State{}, fmt.Errorf("state %q: %w", key, err)
+	}
+	return s, nil
+}
@@ -0,0 +1,34 @@
+package snowflake
+
+import "time"
+
+// Stats is a point-in-time snapshot of a generator's state, suitable for
+// exporting as metrics or logging during postmortems.
+type Stats struct {
+	Version       Version
+	NodeID        uint64
+	BootNonce     uint64
+	LastTimestamp uint64
+	Sequence      uint64
+	Issued        uint64
+
+	// Remaining is the time left until the layout's timestamp field is
+	// exhausted, saturating at the maximum time.Duration
+	Remaining time.Duration
+}
+
+// Stats returns a snapshot of the generator's current state
+func (g *Generator) Stats() Stats {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	return Stats{
+		Version:       g.layout.Version,
+		NodeID:        g.nodeID,
+		BootNonce:     g.bootNonce,
+		LastTimestamp: g.lastTimestamp,
+		Sequence:      g.sequence,
+		Issued:        g.issued,
+		Remaining:     time.Until(g.layout.ExhaustionTime()),
+	}
+}
@@ -0,0 +1,85 @@
+package snowflake
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestStats(t *testing.T) {
+	gen, err := NewGenerator(Config{Version: Version0, NodeID: 7})
+	if err != nil {
+		t.Fatalf("Failed to create generator: %v", err)
+	}
+
+	const numIDs = 10
+	for i := 0; i < numIDs; i++ {
+		if _, err := gen.NextID(); err != nil {
+			t.Fatalf("Failed to generate ID: %v", err)
+		}
+	}
+
+	stats := gen.Stats()
+	if stats.NodeID != 7 {
+		t.Errorf("Expected node ID 7, got %d", stats.NodeID)
+	}
+	if stats.Issued != numIDs {
+		t.Errorf("Expected %d issued IDs, got %d", numIDs, stats.Issued)
+	}
+	if stats.LastTimestamp == 0 {
+		t.Error("Expected non-zero last timestamp")
+	}
+	if stats.BootNonce == 0 || stats.BootNonce != gen.BootNonce() {
+		t.Errorf("Unexpected boot nonce %d (generator reports %d)", stats.BootNonce, gen.BootNonce())
+	}
+}
+
+func TestBootNonce_DiffersPerIncarnation(t *testing.T) {
+	gen1, err := NewGenerator(Config{Version: Version0, NodeID: 1})
+	if err != nil {
+		t.Fatalf("Failed to create generator 1: %v", err)
+	}
+	gen2, err := NewGenerator(Config{Version: Version0, NodeID: 1})
+	if err != nil {
+		t.Fatalf("Failed to create generator 2: %v", err)
+	}
+
+	if gen1.BootNonce() == gen2.BootNonce() {
+		t.Errorf("Expected distinct boot nonces, both were %d", gen1.BootNonce())
+	}
+}
+
+func TestMinRemaining(t *testing.T) {
+	// 32 bits of milliseconds last ~49.7 days; 30 days have already passed
+	shortLived := VersionLayout{
+		Version:      6,
+		VersionBits:  3,
+		TimeBits:     32,
+		NodeBits:     10,
+		SequenceBits: 19,
+		TimeUnit:     time.Millisecond,
+		Epoch:        time.Now().Add(-30 * 24 * time.Hour),
+		MaxNodeID:    (1 << 10) - 1,
+		MaxSequence:  (1 << 19) - 1,
+		MaxTimestamp: (1 << 32) - 1,
+	}
+	withTestLayout(t, &shortLived)
+
+	gen, err := NewGenerator(Config{Version: 6, NodeID: 1, MinRemaining: 10 * 24 * time.Hour})
+	if err != nil {
+		t.Fatalf("Failed to create generator: %v", err)
+	}
+	if _, err := gen.NextID(); err != nil {
+		t.Fatalf("Failed to generate ID: %v", err)
+	}
+
+	remaining := gen.Stats().Remaining
+	if remaining < 19*24*time.Hour || remaining > 20*24*time.Hour {
+		t.Errorf("Expected ~19.7 days remaining, got %s", remaining)
+	}
+
+	_, err = NewGenerator(Config{Version: 6, NodeID: 1, MinRemaining: 30 * 24 * time.Hour})
+	if !errors.Is(err, ErrHorizonReached) {
+		t.Errorf("Expected ErrHorizonReached, got %v", err)
+	}
+}
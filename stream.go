@@ -0,0 +1,127 @@
+package snowflake
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+// streamBufferSize is the read and write buffer size of DecodeStream
+const streamBufferSize = 64 << 10
+
+// DecodeStream reads IDs from r as 8-byte integers in the given byte order
+// and writes each one decoded to w as a JSON line:
+//
+//	{"id":"1234","version":0,"timestamp":5,"time":"2026-01-01T00:00:00.005Z","node":1,"sequence":0}
+//
+// The ID is a string, since JSON numbers lose precision past 2^53. Custom
+// fields follow in a "fields" object. With a nil layout each ID is decoded
+// with the registered layout for its version, as by Decode; versionless
+// IDs need their layout passed.
+//
+// Memory use is bounded by fixed buffers whatever the input size, so it
+// suits pipelines over multi-gigabyte files. It returns the number of IDs
+// written. Input ending partway through an ID is io.ErrUnexpectedEOF.
+func DecodeStream(w io.Writer, r io.Reader, order binary.ByteOrder, layout *VersionLayout) (n int64, err error) {
+	if layout != nil {
+		if err := layout.validate(); err != nil {
+			return 0, err
+		}
+	}
+
+	in := bufio.NewReaderSize(r, streamBufferSize)
+	out := bufio.NewWriterSize(w, streamBufferSize)
+	keys := make(map[*VersionLayout][][]byte)
+
+	// Write out the lines decoded before any error
+	defer func() {
+		if flushErr := out.Flush(); err == nil {
+			err = flushErr
+		}
+	}()
+
+	var (
+		raw  [8]byte
+		line []byte
+	)
+	for {
+		if _, err := io.ReadFull(in, raw[:]); err == io.EOF {
+			break
+		} else if err != nil {
+			return n, fmt.Errorf("reading ID %d: %w", n, err)
+		}
+		id := order.Uint64(raw[:])
+
+		l := layout
+		if l == nil {
+			var err error
+			if l, err = extractVersion(id); err != nil {
+				return n, fmt.Errorf("ID %d at index %d: %w", id, n, err)
+			}
+		} else if l.VersionBits > 0 && !l.matches(id) {
+			return n, fmt.Errorf("%w: ID %d at index %d does not carry version %d", ErrInvalidVersion, id, n, l.Version)
+		}
+
+		fieldKeys, ok := keys[l]
+		if !ok {
+			fieldKeys = jsonFieldKeys(l)
+			keys[l] = fieldKeys
+		}
+
+		line = appendDecodedJSON(line[:0], id, l, fieldKeys)
+		if _, err := out.Write(line); err != nil {
+			return n, err
+		}
+		n++
+	}
+
+	return n, nil
+}
+
+// jsonFieldKeys returns the layout's custom field names as quoted JSON
+// object keys, in layout order
+func jsonFieldKeys(l *VersionLayout) [][]byte {
+	keys := make([][]byte, len(l.Fields))
+	for i, f := range l.Fields {
+		keys[i], _ = json.Marshal(f.Name)
+	}
+	return keys
+}
+
+// appendDecodedJSON appends id's JSON line to buf
+func appendDecodedJSON(buf []byte, id uint64, l *VersionLayout, fieldKeys [][]byte) []byte {
+	timestamp := (id >> l.timeShift()) & l.MaxTimestamp
+
+	buf = append(buf, `{"id":"`...)
+	buf = strconv.AppendUint(buf, id, 10)
+	buf = append(buf, `","version":`...)
+	buf = strconv.AppendUint(buf, uint64(l.Version), 10)
+	buf = append(buf, `,"timestamp":`...)
+	buf = strconv.AppendUint(buf, timestamp, 10)
+	buf = append(buf, `,"time":"`...)
+	buf = l.timeOf(timestamp).UTC().AppendFormat(buf, time.RFC3339Nano)
+	buf = append(buf, `","node":`...)
+	buf = strconv.AppendUint(buf, (id>>l.nodeShift())&l.MaxNodeID, 10)
+	buf = append(buf, `,"sequence":`...)
+	buf = strconv.AppendUint(buf, (id>>l.sequenceShift())&l.MaxSequence, 10)
+
+	if len(l.Fields) > 0 {
+		buf = append(buf, `,"fields":{`...)
+		for i, f := range l.Fields {
+			if i > 0 {
+				buf = append(buf, ',')
+			}
+			shift, bits, _ := l.field(f.Name)
+			buf = append(buf, fieldKeys[i]...)
+			buf = append(buf, ':')
+			buf = strconv.AppendUint(buf, (id>>shift)&mask(bits), 10)
+		}
+		buf = append(buf, '}')
+	}
+
+	return append(buf, '}', '\n')
+}
@@ -0,0 +1,113 @@
+package snowflake
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// streamLine is the JSON line written by DecodeStream
+type streamLine struct {
+	ID        string            `json:"id"`
+	Version   Version           `json:"version"`
+	Timestamp uint64            `json:"timestamp"`
+	Time      time.Time         `json:"time"`
+	Node      uint64            `json:"node"`
+	Sequence  uint64            `json:"sequence"`
+	Fields    map[string]uint64 `json:"fields"`
+}
+
+func encodeStream(order binary.ByteOrder, ids ...uint64) []byte {
+	buf := make([]byte, 8*len(ids))
+	for i, id := range ids {
+		order.PutUint64(buf[8*i:], id)
+	}
+	return buf
+}
+
+func decodeStreamLines(t *testing.T, out string) []streamLine {
+	t.Helper()
+	var lines []streamLine
+	for _, s := range strings.Split(strings.TrimSuffix(out, "\n"), "\n") {
+		var line streamLine
+		if err := json.Unmarshal([]byte(s), &line); err != nil {
+			t.Fatalf("Invalid JSON line %q: %v", s, err)
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+func TestDecodeStream(t *testing.T) {
+	gen, _ := NewGenerator(Config{Version: Version0, NodeID: 7})
+	ids, _ := gen.NextIDs(3)
+
+	for _, order := range []binary.ByteOrder{binary.LittleEndian, binary.BigEndian} {
+		var out bytes.Buffer
+		n, err := DecodeStream(&out, bytes.NewReader(encodeStream(order, ids...)), order, nil)
+		if err != nil || n != 3 {
+			t.Fatalf("%s: expected 3 IDs, got %d (%v)", order, n, err)
+		}
+
+		for i, line := range decodeStreamLines(t, out.String()) {
+			want, _ := Decode(ids[i])
+			if line.ID != strconv.FormatUint(ids[i], 10) || line.Node != 7 ||
+				line.Sequence != want.Sequence || !line.Time.Equal(want.Time) || line.Fields != nil {
+				t.Errorf("%s: line %d is %+v, want %s", order, i, line, want)
+			}
+		}
+	}
+}
+
+func TestDecodeStream_Layout(t *testing.T) {
+	layout, err := NewLayoutBuilder().
+		Time(41, time.Millisecond, EpochTwitter2010).
+		Field("region", 4).
+		Field("node", 6).
+		Sequence(12).
+		Build()
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+	id := uint64(1000)<<22 | 3<<18 | 5<<12 | 9
+
+	var out bytes.Buffer
+	if _, err := DecodeStream(&out, bytes.NewReader(encodeStream(binary.BigEndian, id)), binary.BigEndian, layout); err != nil {
+		t.Fatalf("DecodeStream failed: %v", err)
+	}
+	line := decodeStreamLines(t, out.String())[0]
+	if line.Timestamp != 1000 || line.Node != 5 || line.Sequence != 9 || line.Fields["region"] != 3 {
+		t.Errorf("Unexpected line: %+v", line)
+	}
+}
+
+func TestDecodeStream_Errors(t *testing.T) {
+	gen, _ := NewGenerator(Config{Version: Version0})
+	id, _ := gen.NextID()
+
+	// A truncated record fails after the complete ones are written
+	var out bytes.Buffer
+	input := encodeStream(binary.LittleEndian, id, id)[:12]
+	n, err := DecodeStream(&out, bytes.NewReader(input), binary.LittleEndian, nil)
+	if n != 1 || !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Errorf("Expected 1 ID then io.ErrUnexpectedEOF, got %d, %v", n, err)
+	}
+	if lines := bytes.Count(out.Bytes(), []byte("\n")); lines != 1 {
+		t.Errorf("Expected the decoded line to be flushed, got %d lines", lines)
+	}
+
+	unknown := uint64(7) << 61
+	if _, err := DecodeStream(io.Discard, bytes.NewReader(encodeStream(binary.LittleEndian, unknown)), binary.LittleEndian, nil); !errors.Is(err, ErrInvalidVersion) {
+		t.Errorf("Expected ErrInvalidVersion for an unregistered version, got %v", err)
+	}
+
+	if n, err := DecodeStream(io.Discard, bytes.NewReader(nil), binary.LittleEndian, nil); n != 0 || err != nil {
+		t.Errorf("Expected empty input to succeed, got %d, %v", n, err)
+	}
+}
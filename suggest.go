@@ -0,0 +1,74 @@
+package snowflake
+
+import (
+	"fmt"
+	"math"
+	"math/bits"
+	"time"
+)
+
+// suggestUnits are the time units SuggestLayout considers, finest first.
+// Finer units are left out: a generator waiting out an exhausted sequence
+// polls the clock every 100µs, so it could not sustain the planned rate.
+var suggestUnits = []time.Duration{
+	time.Millisecond,
+	10 * time.Millisecond,
+	100 * time.Millisecond,
+	time.Second,
+}
+
+// SuggestLayout computes a bit split for a fleet of up to maxNodes nodes,
+// each issuing up to peakIDsPerSecPerNode IDs per second, that lasts at
+// least desiredLifetime from now.
+//
+// The layout keeps the 3-bit version field and EpochY2026 of the builtin
+// versions, with version number 6, the first user number; change it
+// before registering if 6 is taken. It uses the finest time unit (1ms,
+// 10ms, 100ms or 1s) at which the node and sequence fields fit alongside a
+// long enough time field, which orders IDs most precisely. Spare bits
+// widen the sequence, leaving headroom above the planned peak.
+func SuggestLayout(maxNodes int, peakIDsPerSecPerNode int, desiredLifetime time.Duration) (VersionLayout, error) {
+	if maxNodes < 1 || peakIDsPerSecPerNode < 1 || desiredLifetime <= 0 {
+		return VersionLayout{}, fmt.Errorf("%w: nodes, rate and lifetime must be positive", ErrInvalidLayout)
+	}
+
+	const versionBits = 3
+	epoch := EpochY2026
+	nodeBits := uint8(bits.Len64(uint64(maxNodes - 1)))
+
+	// Seconds from the epoch the time field must reach
+	horizon := max(time.Since(epoch), 0).Seconds() + desiredLifetime.Seconds()
+
+	for _, unit := range suggestUnits {
+		perUnit := math.Ceil(float64(peakIDsPerSecPerNode) * unit.Seconds())
+		sequenceBits := uint8(bits.Len64(uint64(perUnit) - 1))
+		needed := math.Ceil(horizon / unit.Seconds())
+		if needed >= math.MaxInt64 {
+			continue
+		}
+		timeBits := uint8(bits.Len64(uint64(needed)))
+
+		spare := 64 - versionBits - int(nodeBits) - int(sequenceBits) - int(timeBits)
+		if spare < 0 {
+			continue
+		}
+		sequenceBits += uint8(spare)
+
+		layout := VersionLayout{
+			Version:      6,
+			VersionBits:  versionBits,
+			TimeBits:     timeBits,
+			NodeBits:     nodeBits,
+			SequenceBits: sequenceBits,
+			TimeUnit:     unit,
+			Epoch:        epoch,
+			MaxTimestamp: mask(timeBits),
+			MaxNodeID:    mask(nodeBits),
+			MaxSequence:  mask(sequenceBits),
+		}
+		return layout, layout.validate()
+	}
+
+	return VersionLayout{}, fmt.Errorf("%w: no 64-bit layout fits %d nodes at %d IDs/s each for %s",
+		ErrInvalidLayout, maxNodes, peakIDsPerSecPerNode, desiredLifetime)
+}
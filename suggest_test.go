@@ -0,0 +1,58 @@
+package snowflake
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestSuggestLayout(t *testing.T) {
+	const year = 365 * 24 * time.Hour
+
+	tests := []struct {
+		name     string
+		nodes    int
+		rate     int
+		lifetime time.Duration
+		wantUnit time.Duration
+	}{
+		{"IoT fleet", 50_000, 10, 100 * year, time.Millisecond},
+		{"web service", 256, 100_000, 50 * year, time.Millisecond},
+		{"dense fleet", 4096, 600_000, 20 * year, 10 * time.Millisecond},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			layout, err := SuggestLayout(tt.nodes, tt.rate, tt.lifetime)
+			if err != nil {
+				t.Fatalf("SuggestLayout failed: %v", err)
+			}
+			if err := layout.Validate(); err != nil {
+				t.Errorf("Suggested layout is invalid: %v", err)
+			}
+			if layout.TimeUnit != tt.wantUnit {
+				t.Errorf("Expected unit %s, got %s", tt.wantUnit, layout.TimeUnit)
+			}
+			if layout.MaxNodes() < uint64(tt.nodes) {
+				t.Errorf("Expected room for %d nodes, got %d", tt.nodes, layout.MaxNodes())
+			}
+			if layout.MaxIDsPerSecond() < float64(tt.rate) {
+				t.Errorf("Expected %d IDs/s per node, got %.0f", tt.rate, layout.MaxIDsPerSecond())
+			}
+			if end := time.Now().Add(tt.lifetime); layout.ExhaustionTime().Before(end) {
+				t.Errorf("Expected the layout to last until %s, got %s", end, layout.ExhaustionTime())
+			}
+		})
+	}
+}
+
+func TestSuggestLayout_Invalid(t *testing.T) {
+	// Twitter's split needs all 64 bits for 69 years; there is no room
+	// for a version field
+	if _, err := SuggestLayout(1024, 4_096_000, 69*365*24*time.Hour); !errors.Is(err, ErrInvalidLayout) {
+		t.Errorf("Expected ErrInvalidLayout for an impossible split, got %v", err)
+	}
+	if _, err := SuggestLayout(0, 1, time.Hour); !errors.Is(err, ErrInvalidLayout) {
+		t.Errorf("Expected ErrInvalidLayout for zero nodes, got %v", err)
+	}
+}
@@ -0,0 +1,28 @@
+package snowflake
+
+// TenantField is the name of the optional custom field holding a tenant
+// ID, for multi-tenant services that partition rows by tenant. Add it to a
+// layout with LayoutBuilder.Field(TenantField, bits), set a fixed tenant
+// with Config.Fields, or set it per call with NextIDForTenant.
+//
+// Like every custom field the tenant sits below the time field, so a time
+// window's ID range spans all tenants; partition or filter on the field
+// value, (id >> Shifts().Fields[TenantField]) & (1<<bits - 1).
+const TenantField = "tenant"
+
+// maxTenantBits is the widest tenant field. Wider tenant spaces leave too
+// few bits for time and sequence; map tenants onto shards instead.
+const maxTenantBits = 24
+
+// NextIDForTenant generates the next ID with its tenant field set to
+// tenantID. IDs issued within the same time unit for different tenants are
+// not ordered by issue time.
+func (g *Generator) NextIDForTenant(tenantID uint64) (uint64, error) {
+	return g.nextIDWithField(TenantField, tenantID)
+}
+
+// TenantID returns the ID's tenant field. It reports false if the layout
+// has no tenant field.
+func (d *DecodedID) TenantID() (uint64, bool) {
+	return d.Field(TenantField)
+}
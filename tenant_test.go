@@ -0,0 +1,55 @@
+package snowflake
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestNextIDForTenant(t *testing.T) {
+	layout, err := NewLayoutBuilder().
+		Version(6, 3).
+		Time(41, time.Millisecond, EpochY2026).
+		Field(TenantField, 12).
+		Field("node", 4).
+		Sequence(4).
+		Build()
+	if err != nil {
+		t.Fatalf("Build failed: %v", err)
+	}
+
+	gen, err := NewGenerator(Config{Layout: layout, NodeID: 5, Fields: map[string]uint64{TenantField: 9}})
+	if err != nil {
+		t.Fatalf("Failed to create generator: %v", err)
+	}
+
+	id, err := gen.NextID()
+	if err != nil {
+		t.Fatalf("NextID failed: %v", err)
+	}
+	decoded, _ := DecodeWithLayout(id, layout)
+	if tenant, ok := decoded.TenantID(); !ok || tenant != 9 {
+		t.Errorf("Expected the configured tenant 9, got %d (%v)", tenant, ok)
+	}
+
+	id, err = gen.NextIDForTenant(4000)
+	if err != nil {
+		t.Fatalf("NextIDForTenant failed: %v", err)
+	}
+	decoded, _ = DecodeWithLayout(id, layout)
+	if tenant, _ := decoded.TenantID(); tenant != 4000 || decoded.NodeID != 5 {
+		t.Errorf("Expected tenant 4000 on node 5, got %s (tenant %d)", decoded, tenant)
+	}
+
+	if _, err := gen.NextIDForTenant(1 << 12); !errors.Is(err, ErrComponentRange) {
+		t.Errorf("Expected ErrComponentRange for a tenant beyond 12 bits, got %v", err)
+	}
+	if _, err := NewGenerator(Config{Layout: layout, Fields: map[string]uint64{TenantField: 1 << 12}}); !errors.Is(err, ErrInvalidLayout) {
+		t.Errorf("Expected ErrInvalidLayout for a configured tenant beyond 12 bits, got %v", err)
+	}
+
+	_, err = NewLayoutBuilder().Time(30, time.Second, EpochY2026).Field(TenantField, 25).Sequence(8).Build()
+	if !errors.Is(err, ErrInvalidLayout) {
+		t.Errorf("Expected ErrInvalidLayout for a 25-bit tenant field, got %v", err)
+	}
+}
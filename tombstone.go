@@ -0,0 +1,56 @@
+package snowflake
+
+import (
+	"errors"
+	"fmt"
+)
+
+// TombstoneField is the name of the optional one-bit custom field marking
+// soft-delete tombstones. Generators always leave it clear; Tombstone sets
+// it, so a deleted row's tombstone ID is derived from, and decodes to the
+// same components as, the original.
+const TombstoneField = "tombstone"
+
+var ErrAlreadyTombstone = errors.New("ID is already a tombstone")
+
+// Tombstone returns the tombstone ID for id. Its layout must have a
+// TombstoneField.
+func Tombstone(id uint64) (uint64, error) {
+	bit, err := tombstoneBit(id)
+	if err != nil {
+		return 0, err
+	}
+	if id&bit != 0 {
+		return 0, fmt.Errorf("%w: %d", ErrAlreadyTombstone, id)
+	}
+	return id | bit, nil
+}
+
+// IsTombstone reports whether id is a tombstone ID
+func IsTombstone(id uint64) bool {
+	bit, err := tombstoneBit(id)
+	return err == nil && id&bit != 0
+}
+
+// TombstoneOriginal returns the ID a tombstone was derived from. IDs that
+// are not tombstones are returned unchanged.
+func TombstoneOriginal(id uint64) uint64 {
+	bit, err := tombstoneBit(id)
+	if err != nil {
+		return id
+	}
+	return id &^ bit
+}
+
+// tombstoneBit returns the tombstone field bit of id's layout
+func tombstoneBit(id uint64) (uint64, error) {
+	layout, err := extractVersion(id)
+	if err != nil {
+		return 0, err
+	}
+	shift, _, ok := layout.field(TombstoneField)
+	if !ok {
+		return 0, fmt.Errorf("%w: version %d has no %s field", ErrInvalidLayout, layout.Version, TombstoneField)
+	}
+	return 1 << shift, nil
+}
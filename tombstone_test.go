@@ -0,0 +1,82 @@
+package snowflake
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestTombstone(t *testing.T) {
+	layout, err := NewLayoutBuilder().
+		Version(6, 3).
+		Time(42, time.Millisecond, EpochY2026).
+		Field(TombstoneField, 1).
+		Field("node", 8).
+		Sequence(10).
+		Build()
+	if err != nil {
+		t.Fatalf("Failed to build layout: %v", err)
+	}
+	withTestLayout(t, layout)
+
+	gen, err := NewGenerator(Config{Version: 6, NodeID: 4})
+	if err != nil {
+		t.Fatalf("Failed to create generator: %v", err)
+	}
+	id, err := gen.NextID()
+	if err != nil {
+		t.Fatalf("NextID failed: %v", err)
+	}
+	if IsTombstone(id) {
+		t.Fatal("Freshly issued ID is a tombstone")
+	}
+
+	tomb, err := Tombstone(id)
+	if err != nil {
+		t.Fatalf("Failed to derive tombstone: %v", err)
+	}
+	if tomb == id || !IsTombstone(tomb) || TombstoneOriginal(tomb) != id {
+		t.Errorf("Unexpected tombstone %d for %d", tomb, id)
+	}
+
+	orig, err := Decode(id)
+	if err != nil {
+		t.Fatalf("Failed to decode ID: %v", err)
+	}
+	decoded, err := Decode(tomb)
+	if err != nil {
+		t.Fatalf("Failed to decode tombstone: %v", err)
+	}
+	if decoded.Timestamp != orig.Timestamp || decoded.NodeID != orig.NodeID || decoded.Sequence != orig.Sequence {
+		t.Errorf("Tombstone components %s differ from original %s", decoded, orig)
+	}
+	if v, _ := decoded.Field(TombstoneField); v != 1 {
+		t.Errorf("Expected tombstone field 1, got %d", v)
+	}
+
+	if _, err := Tombstone(tomb); !errors.Is(err, ErrAlreadyTombstone) {
+		t.Errorf("Expected ErrAlreadyTombstone, got %v", err)
+	}
+
+	if _, err := NewGenerator(Config{Version: 6, Fields: map[string]uint64{TombstoneField: 1}}); !errors.Is(err, ErrInvalidLayout) {
+		t.Errorf("Expected generators to refuse the tombstone field, got %v", err)
+	}
+}
+
+func TestTombstone_NoField(t *testing.T) {
+	gen, err := NewGenerator(Config{Version: Version0})
+	if err != nil {
+		t.Fatalf("Failed to create generator: %v", err)
+	}
+	id, err := gen.NextID()
+	if err != nil {
+		t.Fatalf("NextID failed: %v", err)
+	}
+
+	if _, err := Tombstone(id); !errors.Is(err, ErrInvalidLayout) {
+		t.Errorf("Expected ErrInvalidLayout, got %v", err)
+	}
+	if IsTombstone(id) || TombstoneOriginal(id) != id {
+		t.Error("Expected IDs without a tombstone field to be left alone")
+	}
+}
@@ -0,0 +1,136 @@
+package snowflake
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"sync"
+)
+
+// TxScope binds a generator to the lifecycle of one database transaction.
+// The transaction is begun on first use, and every ID minted through the
+// scope is remembered so that IDs belonging to a rolled-back transaction
+// can be compensated for or logged.
+//
+//	scope := snowflake.NewTxScope(ctx, db, gen, nil)
+//	defer scope.Rollback()
+//	tx, err := scope.Tx()
+//	id, err := scope.NextID()
+//	...
+//	return scope.Commit()
+type TxScope struct {
+	ctx  context.Context
+	db   *sql.DB
+	gen  *Generator
+	opts *sql.TxOptions
+
+	mu   sync.Mutex
+	tx   *sql.Tx
+	done bool
+	ids  []uint64
+}
+
+// NewTxScope returns a scope that begins a transaction on db with opts when
+// first needed. IDs are minted on behalf of the caller tagged in ctx.
+func NewTxScope(ctx context.Context, db *sql.DB, gen *Generator, opts *sql.TxOptions) *TxScope {
+	return &TxScope{ctx: ctx, db: db, gen: gen, opts: opts}
+}
+
+// Tx returns the scope's transaction, beginning it if necessary
+func (s *TxScope) Tx() (*sql.Tx, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.begin()
+}
+
+// NextID begins the transaction if necessary and mints an ID for it
+func (s *TxScope) NextID() (uint64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, err := s.begin(); err != nil {
+		return 0, err
+	}
+
+	id, err := s.gen.NextIDContext(s.ctx)
+	if err != nil {
+		return 0, err
+	}
+	s.ids = append(s.ids, id)
+
+	return id, nil
+}
+
+// IDs returns the IDs minted through the scope so far
+func (s *TxScope) IDs() []uint64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]uint64(nil), s.ids...)
+}
+
+// Commit commits the transaction. It is a no-op if the transaction was
+// never begun, and fails with sql.ErrTxDone once the scope has been
+// committed or rolled back. If the commit fails the scope stays open, so
+// a deferred Rollback still returns the IDs minted for the lost
+// transaction.
+func (s *TxScope) Commit() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.done {
+		return sql.ErrTxDone
+	}
+	if s.tx == nil {
+		s.done = true
+		return nil
+	}
+	if err := s.tx.Commit(); err != nil {
+		return err
+	}
+	s.done = true
+
+	return nil
+}
+
+// Rollback rolls the transaction back and returns the IDs minted for it.
+// It is a no-op returning no IDs once the scope has been committed or
+// rolled back, so it is safe to defer.
+func (s *TxScope) Rollback() ([]uint64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.done {
+		return nil, nil
+	}
+	s.done = true
+
+	ids := s.ids
+	s.ids = nil
+	if s.tx == nil {
+		return ids, nil
+	}
+
+	// A failed commit or cancelled context has already ended the
+	// transaction without committing it
+	if err := s.tx.Rollback(); err != nil && !errors.Is(err, sql.ErrTxDone) {
+		return ids, err
+	}
+	return ids, nil
+}
+
+// begin starts the transaction on first use. The caller must hold s.mu.
+func (s *TxScope) begin() (*sql.Tx, error) {
+	if s.done {
+		return nil, sql.ErrTxDone
+	}
+	if s.tx == nil {
+		tx, err := s.db.BeginTx(s.ctx, s.opts)
+		if err != nil {
+			return nil, err
+		}
+		s.tx = tx
+	}
+	return s.tx, nil
+}
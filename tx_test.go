@@ -0,0 +1,174 @@
+package snowflake
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"sync/atomic"
+	"testing"
+)
+
+// txDriver is a minimal database/sql driver counting transaction outcomes
+type txDriver struct {
+	begun, committed, rolledBack atomic.Int32
+	commitErr                    error
+}
+
+func (d *txDriver) Open(string) (driver.Conn, error) { return txConn{d}, nil }
+
+type txConn struct{ d *txDriver }
+
+func (c txConn) Prepare(string) (driver.Stmt, error) { return nil, errors.New("not supported") }
+func (c txConn) Close() error                        { return nil }
+func (c txConn) Begin() (driver.Tx, error) {
+	c.d.begun.Add(1)
+	return txConn(c), nil
+}
+func (c txConn) Commit() error {
+	if c.d.commitErr != nil {
+		return c.d.commitErr
+	}
+	c.d.committed.Add(1)
+	return nil
+}
+func (c txConn) Rollback() error {
+	c.d.rolledBack.Add(1)
+	return nil
+}
+
+func openTxDB(t *testing.T) (*sql.DB, *txDriver) {
+	t.Helper()
+	d := &txDriver{}
+	name := "snowflake-tx-" + t.Name()
+	sql.Register(name, d)
+
+	db, err := sql.Open(name, "")
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db, d
+}
+
+func TestTxScope_Rollback(t *testing.T) {
+	db, d := openTxDB(t)
+	gen, _ := NewGenerator(Config{Version: Version0})
+	scope := NewTxScope(context.Background(), db, gen, nil)
+
+	var minted []uint64
+	for i := 0; i < 3; i++ {
+		id, err := scope.NextID()
+		if err != nil {
+			t.Fatalf("Failed to generate ID: %v", err)
+		}
+		minted = append(minted, id)
+	}
+	if d.begun.Load() != 1 {
+		t.Errorf("Expected one transaction, got %d", d.begun.Load())
+	}
+
+	ids, err := scope.Rollback()
+	if err != nil {
+		t.Fatalf("Failed to roll back: %v", err)
+	}
+	if len(ids) != len(minted) || ids[0] != minted[0] || ids[2] != minted[2] {
+		t.Errorf("Expected rolled-back IDs %v, got %v", minted, ids)
+	}
+	if d.rolledBack.Load() != 1 {
+		t.Errorf("Expected one rollback, got %d", d.rolledBack.Load())
+	}
+
+	if _, err := scope.NextID(); !errors.Is(err, sql.ErrTxDone) {
+		t.Errorf("Expected sql.ErrTxDone after rollback, got %v", err)
+	}
+}
+
+func TestTxScope_CommitThenDeferredRollback(t *testing.T) {
+	db, d := openTxDB(t)
+	gen, _ := NewGenerator(Config{Version: Version0})
+	scope := NewTxScope(context.Background(), db, gen, nil)
+
+	if _, err := scope.Tx(); err != nil {
+		t.Fatalf("Failed to begin: %v", err)
+	}
+	if _, err := scope.NextID(); err != nil {
+		t.Fatalf("Failed to generate ID: %v", err)
+	}
+	if err := scope.Commit(); err != nil {
+		t.Fatalf("Failed to commit: %v", err)
+	}
+
+	ids, err := scope.Rollback()
+	if ids != nil || err != nil {
+		t.Errorf("Expected rollback after commit to be a no-op, got %v, %v", ids, err)
+	}
+	if d.begun.Load() != 1 || d.committed.Load() != 1 || d.rolledBack.Load() != 0 {
+		t.Errorf("Unexpected transaction counts: begun %d, committed %d, rolled back %d",
+			d.begun.Load(), d.committed.Load(), d.rolledBack.Load())
+	}
+}
+
+func TestTxScope_CommitFailure(t *testing.T) {
+	db, d := openTxDB(t)
+	d.commitErr = errors.New("serialization failure")
+	gen, _ := NewGenerator(Config{Version: Version0})
+	scope := NewTxScope(context.Background(), db, gen, nil)
+
+	id, err := scope.NextID()
+	if err != nil {
+		t.Fatalf("Failed to generate ID: %v", err)
+	}
+	if err := scope.Commit(); !errors.Is(err, d.commitErr) {
+		t.Fatalf("Expected the commit error, got %v", err)
+	}
+
+	ids, err := scope.Rollback()
+	if err != nil {
+		t.Fatalf("Failed to roll back: %v", err)
+	}
+	if len(ids) != 1 || ids[0] != id {
+		t.Errorf("Expected the uncommitted ID %d, got %v", id, ids)
+	}
+}
+
+func TestTxScope_CommitAfterRollback(t *testing.T) {
+	db, d := openTxDB(t)
+	gen, _ := NewGenerator(Config{Version: Version0})
+	scope := NewTxScope(context.Background(), db, gen, nil)
+
+	if _, err := scope.NextID(); err != nil {
+		t.Fatalf("Failed to generate ID: %v", err)
+	}
+	if _, err := scope.Rollback(); err != nil {
+		t.Fatalf("Failed to roll back: %v", err)
+	}
+	if err := scope.Commit(); !errors.Is(err, sql.ErrTxDone) {
+		t.Errorf("Expected sql.ErrTxDone committing a rolled-back scope, got %v", err)
+	}
+	if d.committed.Load() != 0 {
+		t.Errorf("Expected no commit, got %d", d.committed.Load())
+	}
+
+	// A second commit fails the same way
+	scope = NewTxScope(context.Background(), db, gen, nil)
+	if err := scope.Commit(); err != nil {
+		t.Fatalf("Commit of an unused scope failed: %v", err)
+	}
+	if err := scope.Commit(); !errors.Is(err, sql.ErrTxDone) {
+		t.Errorf("Expected sql.ErrTxDone on a second commit, got %v", err)
+	}
+}
+
+func TestTxScope_Lazy(t *testing.T) {
+	db, d := openTxDB(t)
+	gen, _ := NewGenerator(Config{Version: Version0})
+	scope := NewTxScope(context.Background(), db, gen, nil)
+
+	if err := scope.Commit(); err != nil {
+		t.Fatalf("Failed to commit: %v", err)
+	}
+	if d.begun.Load() != 0 {
+		t.Errorf("Expected no transaction to be begun, got %d", d.begun.Load())
+	}
+}
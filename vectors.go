@@ -0,0 +1,92 @@
+package snowflake
+
+import (
+	"maps"
+	"time"
+)
+
+// ReferenceVector pairs ID components with the ID this package encodes
+// them to. Ports to other languages can check their encoders and decoders
+// against the vectors byte for byte.
+type ReferenceVector struct {
+	Version Version `json:"version"`
+	// Layout names the unregistered layout the vector belongs to (see
+	// ReferenceVectors), or is empty for the registered layout Version
+	Layout    string            `json:"layout,omitempty"`
+	Timestamp uint64            `json:"timestamp"`
+	NodeID    uint64            `json:"node_id"`
+	Sequence  uint64            `json:"sequence"`
+	Fields    map[string]uint64 `json:"fields,omitempty"`
+	Time      time.Time         `json:"time"`
+	ID        uint64            `json:"id"`
+}
+
+// referenceLayouts are the unregistered layouts ReferenceVectors covers
+// besides the registered ones, so ports can check custom fields too
+var referenceLayouts = []struct {
+	name   string
+	layout func() *VersionLayout
+}{
+	// Classic Twitter: [41 bits time][5 bits datacenter][5 bits worker][12 bits sequence]
+	{"twitter-datacenter", func() *VersionLayout {
+		layout, _ := DatacenterLayout(TwitterLayout(), 5)
+		return layout
+	}},
+}
+
+// ReferenceVectors returns a deterministic suite of vectors for every
+// registered layout, covering zero, one, midpoint and maximum values of
+// each field. It also covers "twitter-datacenter", TwitterLayout split by
+// DatacenterLayout with 5 datacenter bits, whose vectors name it in Layout
+// and set the custom datacenter field.
+func ReferenceVectors() []ReferenceVector {
+	var vectors []ReferenceVector
+	for _, layout := range Layouts() {
+		vectors = append(vectors, layout.referenceVectors("")...)
+	}
+	for _, ref := range referenceLayouts {
+		vectors = append(vectors, ref.layout().referenceVectors(ref.name)...)
+	}
+	return vectors
+}
+
+func (l *VersionLayout) referenceVectors(name string) []ReferenceVector {
+	timestamps := []uint64{0, 1, l.MaxTimestamp / 2, l.MaxTimestamp}
+	nodes := []uint64{0, 1, l.MaxNodeID}
+	sequences := []uint64{0, 1, l.MaxSequence}
+
+	// Custom fields are zero, one and their maximum together
+	fieldSets := []map[string]uint64{nil}
+	if len(l.Fields) > 0 {
+		fieldSets = []map[string]uint64{{}, {}, {}}
+		for _, f := range l.Fields {
+			fieldSets[0][f.Name], fieldSets[1][f.Name], fieldSets[2][f.Name] = 0, 1, mask(f.Bits)
+		}
+	}
+
+	vectors := make([]ReferenceVector, 0, len(timestamps)*len(nodes)*len(sequences)*len(fieldSets))
+	for _, ts := range timestamps {
+		for _, fields := range fieldSets {
+			for _, node := range nodes {
+				for _, seq := range sequences {
+					id := l.encode(ts, node, seq)
+					for field, value := range fields {
+						shift, _, _ := l.field(field)
+						id |= value << shift
+					}
+					vectors = append(vectors, ReferenceVector{
+						Version:   l.Version,
+						Layout:    name,
+						Timestamp: ts,
+						NodeID:    node,
+						Sequence:  seq,
+						Fields:    maps.Clone(fields),
+						Time:      l.timeOf(ts).UTC(),
+						ID:        id,
+					})
+				}
+			}
+		}
+	}
+	return vectors
+}
@@ -0,0 +1,107 @@
+package snowflake
+
+import (
+	"maps"
+	"testing"
+	"time"
+)
+
+func TestReferenceVectors_RoundTrip(t *testing.T) {
+	vectors := ReferenceVectors()
+	if len(vectors) == 0 {
+		t.Fatal("Expected test vectors")
+	}
+
+	for _, v := range vectors {
+		layout, ok := referenceLayout(v)
+		if !ok {
+			t.Fatalf("Vector %d has no layout %q", v.ID, v.Layout)
+		}
+		decoded, err := DecodeWithLayout(v.ID, layout)
+		if err != nil {
+			t.Fatalf("Failed to decode vector %d: %v", v.ID, err)
+		}
+
+		if decoded.Version != v.Version || decoded.Timestamp != v.Timestamp ||
+			decoded.NodeID != v.NodeID || decoded.Sequence != v.Sequence ||
+			!maps.Equal(decoded.Fields, v.Fields) {
+			t.Errorf("Vector %d decoded to %s, want %+v", v.ID, decoded, v)
+		}
+		if !decoded.Time.Equal(v.Time) {
+			t.Errorf("Vector %d: expected time %s, got %s", v.ID, v.Time, decoded.Time)
+		}
+	}
+}
+
+func TestReferenceVectors_MaxTime(t *testing.T) {
+	// The last millisecond of Version0 lies ~1,115 years past its epoch,
+	// beyond what time.Duration can represent.
+	want := time.Date(3140, 12, 13, 12, 41, 28, 831_000_000, time.UTC)
+
+	for _, v := range ReferenceVectors() {
+		if v.Version == Version0 && v.Layout == "" && v.Timestamp == (1<<45)-1 {
+			if !v.Time.Equal(want) {
+				t.Fatalf("Expected time %s, got %s", want, v.Time)
+			}
+			return
+		}
+	}
+	t.Fatal("No maximum-timestamp vector for Version0")
+}
+
+func TestReferenceVectors_Known(t *testing.T) {
+	// Pinned values guard against accidental encoding changes
+	known := map[[3]uint64]uint64{
+		{0, 0, 0}:                 0,
+		{1, 1, 1}:                 1<<16 | 1<<8 | 1,
+		{(1 << 45) - 1, 255, 255}: (1 << 61) - 1,
+	}
+
+	for _, v := range ReferenceVectors() {
+		if v.Version != Version0 || v.Layout != "" {
+			continue
+		}
+		if want, ok := known[[3]uint64{v.Timestamp, v.NodeID, v.Sequence}]; ok && v.ID != want {
+			t.Errorf("Vector %+v: expected ID %d", v, want)
+		}
+	}
+}
+
+func TestReferenceVectors_CustomField(t *testing.T) {
+	// [41 bits time][5 bits datacenter][5 bits worker][12 bits sequence]
+	known := map[[4]uint64]uint64{
+		{1, 1, 1, 1}:  1<<22 | 1<<17 | 1<<12 | 1,
+		{0, 31, 0, 0}: 31 << 17,
+	}
+
+	found := 0
+	for _, v := range ReferenceVectors() {
+		if v.Layout != "twitter-datacenter" {
+			continue
+		}
+		key := [4]uint64{v.Timestamp, v.Fields[DatacenterField], v.NodeID, v.Sequence}
+		if want, ok := known[key]; ok {
+			found++
+			if v.ID != want {
+				t.Errorf("Vector %+v: expected ID %d", v, want)
+			}
+		}
+	}
+	if found != len(known) {
+		t.Errorf("Expected %d pinned datacenter vectors, found %d", len(known), found)
+	}
+}
+
+// referenceLayout returns the layout v was encoded with
+func referenceLayout(v ReferenceVector) (*VersionLayout, bool) {
+	if v.Layout == "" {
+		layout, ok := LookupLayout(v.Version)
+		return &layout, ok
+	}
+	for _, ref := range referenceLayouts {
+		if ref.name == v.Layout {
+			return ref.layout(), true
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,57 @@
+package snowflake
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+var ErrFutureTimestamp = errors.New("timestamp is in the future")
+
+// verifyClockSkew is how far into the future an ID's timestamp may be
+// before Verify rejects it, allowing for clock skew between nodes
+const verifyClockSkew = time.Minute
+
+// VerifyResult is the outcome of checking a single ID against the
+// registered layouts
+type VerifyResult struct {
+	ID      uint64
+	Decoded *DecodedID
+	Err     error
+}
+
+// Valid reports whether the ID fits a registered layout
+func (r VerifyResult) Valid() bool {
+	return r.Err == nil
+}
+
+// Verify checks that id could have been issued by this package: its version
+// must be registered and its timestamp must not lie in the future.
+func Verify(id uint64) VerifyResult {
+	return verify(id, time.Now())
+}
+
+// VerifyBatch verifies each ID, returning results in the same order. It is
+// intended for cheaply rejecting externally-supplied IDs before insertion.
+func VerifyBatch(ids []uint64) []VerifyResult {
+	now := time.Now()
+	results := make([]VerifyResult, len(ids))
+	for i, id := range ids {
+		results[i] = verify(id, now)
+	}
+	return results
+}
+
+func verify(id uint64, now time.Time) VerifyResult {
+	decoded, err := Decode(id)
+	if err != nil {
+		return VerifyResult{ID: id, Err: err}
+	}
+
+	if decoded.Time.After(now.Add(verifyClockSkew)) {
+		return VerifyResult{ID: id, Decoded: decoded, Err: fmt.Errorf("%w: %s",
+			ErrFutureTimestamp, decoded.Time.Format(time.RFC3339Nano))}
+	}
+
+	return VerifyResult{ID: id, Decoded: decoded}
+}
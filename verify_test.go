@@ -0,0 +1,44 @@
+package snowflake
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestVerifyBatch(t *testing.T) {
+	gen, err := NewGenerator(Config{Version: Version0, NodeID: 3})
+	if err != nil {
+		t.Fatalf("Failed to create generator: %v", err)
+	}
+
+	valid, err := gen.NextID()
+	if err != nil {
+		t.Fatalf("Failed to generate ID: %v", err)
+	}
+
+	layout := versionLayouts[Version0]
+	future := layout.encode(uint64(time.Since(layout.Epoch)/layout.TimeUnit)+uint64(time.Hour/layout.TimeUnit), 3, 0)
+	unknownVersion := uint64(5) << 61
+
+	ids := []uint64{valid, future, unknownVersion}
+	results := VerifyBatch(ids)
+	if len(results) != len(ids) {
+		t.Fatalf("Expected %d results, got %d", len(ids), len(results))
+	}
+	for i, r := range results {
+		if r.ID != ids[i] {
+			t.Errorf("Result %d: expected ID %d, got %d", i, ids[i], r.ID)
+		}
+	}
+
+	if !results[0].Valid() || results[0].Decoded.NodeID != 3 {
+		t.Errorf("Expected generated ID to verify, got %v", results[0].Err)
+	}
+	if !errors.Is(results[1].Err, ErrFutureTimestamp) {
+		t.Errorf("Expected ErrFutureTimestamp, got %v", results[1].Err)
+	}
+	if !errors.Is(results[2].Err, ErrInvalidVersion) {
+		t.Errorf("Expected ErrInvalidVersion, got %v", results[2].Err)
+	}
+}
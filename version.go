@@ -0,0 +1,125 @@
+package snowflake
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// presets maps the names of the preset layouts to their constructors
+var presets = map[string]func() *VersionLayout{
+	"twitter":   TwitterLayout,
+	"sonyflake": SonyflakeLayout,
+	"instagram": InstagramLayout,
+	"discord":   DiscordLayout,
+	"mastodon":  MastodonLayout,
+}
+
+// ParseVersion parses a registered version given as a number ("2"), with a
+// "v" prefix ("v2"), or by its layout name ("micro"). Preset names such as
+// "twitter" name unregistered layouts, not versions; see ParseLayout.
+func ParseVersion(s string) (Version, error) {
+	name := strings.ToLower(strings.TrimSpace(s))
+
+	number := strings.TrimPrefix(name, "v")
+	if n, err := strconv.ParseUint(number, 10, 8); err == nil {
+		if _, ok := lookupLayout(Version(n)); ok {
+			return Version(n), nil
+		}
+		return 0, fmt.Errorf("%w: %d", ErrInvalidVersion, n)
+	}
+
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	for _, layout := range versionLayouts {
+		if layout.Name != "" && strings.ToLower(layout.Name) == name {
+			return layout.Version, nil
+		}
+	}
+	if _, ok := presets[name]; ok {
+		return 0, fmt.Errorf("%w: %q is a preset layout without a version; use ParseLayout", ErrInvalidVersion, s)
+	}
+	return 0, fmt.Errorf("%w: %q", ErrInvalidVersion, s)
+}
+
+// ParseLayout parses a layout given as ParseVersion accepts it, or by the
+// name of a preset ("twitter", "sonyflake", "instagram", "discord",
+// "mastodon"). Registered names take precedence over preset names. The
+// result is a copy the caller may modify.
+func ParseLayout(s string) (*VersionLayout, error) {
+	v, err := ParseVersion(s)
+	if err == nil {
+		if layout, ok := lookupLayout(v); ok {
+			return layout.clone(), nil
+		}
+	}
+	if preset, ok := presets[strings.ToLower(strings.TrimSpace(s))]; ok {
+		return preset(), nil
+	}
+	return nil, err
+}
+
+// LayoutFlag is a flag.Value selecting a layout by version or preset name,
+// as parsed by ParseLayout, e.g. --layout twitter or --layout v2
+type LayoutFlag struct {
+	Layout *VersionLayout // nil until set
+}
+
+// String returns the layout's name, or its version if unnamed
+func (f *LayoutFlag) String() string {
+	switch {
+	case f == nil || f.Layout == nil:
+		return ""
+	case f.Layout.Name != "":
+		return f.Layout.Name
+	default:
+		return f.Layout.Version.String()
+	}
+}
+
+// Set implements flag.Value
+func (f *LayoutFlag) Set(s string) error {
+	layout, err := ParseLayout(s)
+	if err != nil {
+		return err
+	}
+	f.Layout = layout
+	return nil
+}
+
+// Type implements pflag.Value
+func (f *LayoutFlag) Type() string {
+	return "layout"
+}
+
+// parseVersionNumber parses a version number given as "6" or "v6",
+// registered or not
+func parseVersionNumber(s string) (Version, error) {
+	number := strings.TrimPrefix(strings.ToLower(strings.TrimSpace(s)), "v")
+	n, err := strconv.ParseUint(number, 10, 8)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidVersion, s)
+	}
+	return Version(n), nil
+}
+
+// String returns the version as "v<N>"
+func (v Version) String() string {
+	return "v" + strconv.FormatUint(uint64(v), 10)
+}
+
+// Set implements flag.Value
+func (v *Version) Set(s string) error {
+	parsed, err := ParseVersion(s)
+	if err != nil {
+		return err
+	}
+	*v = parsed
+	return nil
+}
+
+// Type implements pflag.Value
+func (v *Version) Type() string {
+	return "version"
+}
@@ -0,0 +1,120 @@
+package snowflake
+
+import (
+	"errors"
+	"flag"
+	"strings"
+	"testing"
+)
+
+func TestParseVersion(t *testing.T) {
+	named := testLayout(6, 3)
+	named.Name = "Acme"
+	withTestLayout(t, named)
+
+	tests := []struct {
+		input string
+		want  Version
+	}{
+		{input: "0", want: Version0},
+		{input: "v0", want: Version0},
+		{input: "V6", want: 6},
+		{input: "acme", want: 6},
+		{input: "ACME", want: 6},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseVersion(tt.input)
+		if err != nil {
+			t.Errorf("ParseVersion(%q) failed: %v", tt.input, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseVersion(%q) = %s, want %s", tt.input, got, tt.want)
+		}
+	}
+
+	for _, input := range []string{"v5", "99", "nope", ""} {
+		if _, err := ParseVersion(input); !errors.Is(err, ErrInvalidVersion) {
+			t.Errorf("ParseVersion(%q): expected ErrInvalidVersion, got %v", input, err)
+		}
+	}
+}
+
+func TestFlagValues(t *testing.T) {
+	var (
+		version Version
+		start   ID
+		nodeID  uint64
+	)
+
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.Var(&version, "version", "layout version")
+	fs.Var(&start, "starting-id", "first ID")
+	fs.Uint64Var(&nodeID, "node-id", 0, "node ID")
+
+	err := fs.Parse([]string{"--version", "v0", "--starting-id", "18446744073709551615", "--node-id", "7"})
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	if version != Version0 || start != ID(^uint64(0)) || nodeID != 7 {
+		t.Errorf("Unexpected flag values: version=%s start=%s node=%d", version, start, nodeID)
+	}
+
+	if err := fs.Parse([]string{"--starting-id", "-1"}); err == nil {
+		t.Error("Expected error for negative ID")
+	}
+	if start.Type() != "id" || version.Type() != "version" {
+		t.Error("Unexpected pflag type names")
+	}
+}
+
+func TestParseLayout(t *testing.T) {
+	var layout LayoutFlag
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.Var(&layout, "version", "layout")
+
+	if err := fs.Parse([]string{"--version", "twitter"}); err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if layout.Layout == nil || !layout.Layout.Epoch.Equal(EpochTwitter2010) || layout.String() != "twitter" {
+		t.Errorf("Expected the Twitter preset, got %+v", layout.Layout)
+	}
+
+	if err := fs.Parse([]string{"--version", "v2"}); err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	if layout.Layout.Version != Version2 || layout.String() != "micro" {
+		t.Errorf("Expected Version2, got %+v", layout.Layout)
+	}
+
+	// Version 1 is reserved, and presets have no version number
+	if _, err := ParseLayout("v1"); !errors.Is(err, ErrInvalidVersion) {
+		t.Errorf("ParseLayout(v1): expected ErrInvalidVersion, got %v", err)
+	}
+	if _, err := ParseVersion("twitter"); !errors.Is(err, ErrInvalidVersion) || !strings.Contains(err.Error(), "ParseLayout") {
+		t.Errorf("ParseVersion(twitter): expected a pointer to ParseLayout, got %v", err)
+	}
+	if layout.Type() != "layout" {
+		t.Error("Unexpected pflag type name")
+	}
+}
+
+func TestRegisterLayout_NameCollision(t *testing.T) {
+	named := testLayout(6, 3)
+	named.Name = "acme"
+	withTestLayout(t, named)
+
+	other := testLayout(7, 3)
+	other.Name = "Acme"
+	if err := RegisterLayout(other); !errors.Is(err, ErrVersionTaken) {
+		t.Errorf("Expected ErrVersionTaken for duplicate name, got %v", err)
+	}
+
+	numeric := testLayout(7, 3)
+	numeric.Name = "v2"
+	if err := RegisterLayout(numeric); !errors.Is(err, ErrInvalidLayout) {
+		t.Errorf("Expected ErrInvalidLayout for numeric name, got %v", err)
+	}
+}
@@ -0,0 +1,28 @@
+package snowflake
+
+import "context"
+
+// Warmup prepares the generator for its first request without issuing an
+// ID. It reads the clock, priming time sources whose first read is slow
+// (such as a PTP device or a quorum of remote clocks), and returns the
+// error the first NextID would fail with, if any, so services can refuse
+// to report ready instead of failing their first request. With a
+// StateStore it also saves the initial high-water mark, which the first
+// NextID would otherwise wait for.
+func (g *Generator) Warmup(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	timestamp, err := g.usableTimestamp()
+	if err != nil {
+		return err
+	}
+	if g.stateStore != nil && timestamp >= g.stateUntil {
+		return g.saveState(timestamp)
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+package snowflake
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestWarmup(t *testing.T) {
+	var reads int
+	clock := ClockFunc(func() (time.Time, error) {
+		reads++
+		return time.Now(), nil
+	})
+
+	gen, err := NewGenerator(Config{Version: Version0, Clock: clock})
+	if err != nil {
+		t.Fatalf("Failed to create generator: %v", err)
+	}
+	reads = 0
+
+	if err := gen.Warmup(context.Background()); err != nil {
+		t.Fatalf("Warmup failed: %v", err)
+	}
+	if reads != 1 {
+		t.Errorf("Expected Warmup to read the clock once, got %d reads", reads)
+	}
+	if stats := gen.Stats(); stats.Issued != 0 {
+		t.Errorf("Expected Warmup to issue no IDs, got %d", stats.Issued)
+	}
+}
+
+func TestWarmup_Errors(t *testing.T) {
+	future := *versionLayouts[Version0]
+	future.Version = 6
+	future.Epoch = time.Now().Add(time.Hour)
+	withTestLayout(t, &future)
+
+	gen, err := NewGenerator(Config{Version: 6, AllowPreEpoch: true})
+	if err != nil {
+		t.Fatalf("Failed to create generator: %v", err)
+	}
+	if err := gen.Warmup(context.Background()); !errors.Is(err, ErrEpochInFuture) {
+		t.Errorf("Expected ErrEpochInFuture, got %v", err)
+	}
+
+	var broken bool
+	clock := ClockFunc(func() (time.Time, error) {
+		if broken {
+			return time.Time{}, ErrClockQuorum
+		}
+		return time.Now(), nil
+	})
+	gen, err = NewGenerator(Config{Version: Version0, Clock: clock})
+	if err != nil {
+		t.Fatalf("Failed to create generator: %v", err)
+	}
+	broken = true
+	if err := gen.Warmup(context.Background()); !errors.Is(err, ErrClockQuorum) {
+		t.Errorf("Expected the clock error, got %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := gen.Warmup(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+}
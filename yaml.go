@@ -0,0 +1,227 @@
+package snowflake
+
+import (
+	"fmt"
+	"time"
+)
+
+// The YAML methods below follow the MarshalYAML/UnmarshalYAML conventions
+// understood by both gopkg.in/yaml.v2 and yaml.v3, so Config and layouts can
+// be embedded in service config files without this package importing a
+// YAML library. Values use text forms: versions as "v0" or layout names,
+// durations as "1ms", epochs as names or RFC 3339 timestamps.
+
+// configYAML is the serializable subset of Config. Runtime objects (Audit,
+// Quotas, Hooks, Clock) cannot be expressed in config files and are omitted.
+type configYAML struct {
+	Version         string            `yaml:"version"`
+	NodeID          uint64            `yaml:"node_id"`
+	Layout          *VersionLayout    `yaml:"layout,omitempty"`
+	NodeBits        uint8             `yaml:"node_bits,omitempty"`
+	SequenceBits    uint8             `yaml:"sequence_bits,omitempty"`
+	TimeUnit        string            `yaml:"time_unit,omitempty"`
+	NotBefore       string            `yaml:"not_before,omitempty"`
+	Fields          map[string]uint64 `yaml:"fields,omitempty"`
+	WaitStrategy    string            `yaml:"wait_strategy,omitempty"`
+	Fair            bool              `yaml:"fair,omitempty"`
+	AllowPreEpoch   bool              `yaml:"allow_pre_epoch,omitempty"`
+	MinRemaining    string            `yaml:"min_remaining,omitempty"`
+	Source          string            `yaml:"source,omitempty"`
+	SmearBatches    bool              `yaml:"smear_batches,omitempty"`
+	DatacenterID    uint64            `yaml:"datacenter_id,omitempty"`
+	WorkerID        uint64            `yaml:"worker_id,omitempty"`
+	PositiveInt64   bool              `yaml:"positive_int64,omitempty"`
+	LifetimeWarning float64           `yaml:"lifetime_warning,omitempty"`
+}
+
+// layoutYAML is the text form of a VersionLayout. The maximums are derived
+// from the field widths.
+type layoutYAML struct {
+	Version      string      `yaml:"version"`
+	Name         string      `yaml:"name,omitempty"`
+	VersionBits  uint8       `yaml:"version_bits"`
+	TimeBits     uint8       `yaml:"time_bits"`
+	NodeBits     uint8       `yaml:"node_bits"`
+	SequenceBits uint8       `yaml:"sequence_bits"`
+	TimeUnit     string      `yaml:"time_unit"`
+	Epoch        string      `yaml:"epoch"`
+	Fields       []fieldYAML `yaml:"fields,omitempty"`
+	NodeLast     bool        `yaml:"node_last,omitempty"`
+}
+
+type fieldYAML struct {
+	Name string `yaml:"name"`
+	Bits uint8  `yaml:"bits"`
+}
+
+// MarshalYAML encodes the version as "v<N>"
+func (v Version) MarshalYAML() (interface{}, error) {
+	return v.String(), nil
+}
+
+// UnmarshalYAML accepts any form ParseVersion does
+func (v *Version) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var s string
+	if err := unmarshal(&s); err != nil {
+		return err
+	}
+	return v.Set(s)
+}
+
+// MarshalYAML encodes the serializable subset of the config
+func (c Config) MarshalYAML() (interface{}, error) {
+	out := configYAML{
+		Version:         c.Version.String(),
+		NodeID:          c.NodeID,
+		Layout:          c.Layout,
+		NodeBits:        c.NodeBits,
+		SequenceBits:    c.SequenceBits,
+		Fields:          c.Fields,
+		Fair:            c.Fair,
+		AllowPreEpoch:   c.AllowPreEpoch,
+		Source:          c.Source,
+		SmearBatches:    c.SmearBatches,
+		DatacenterID:    c.DatacenterID,
+		WorkerID:        c.WorkerID,
+		PositiveInt64:   c.PositiveInt64,
+		LifetimeWarning: c.LifetimeWarning,
+	}
+	if !c.NotBefore.IsZero() {
+		out.NotBefore = c.NotBefore.Format(time.RFC3339Nano)
+	}
+	if c.WaitStrategy != WaitPoll {
+		out.WaitStrategy = c.WaitStrategy.String()
+	}
+	if c.MinRemaining != 0 {
+		out.MinRemaining = c.MinRemaining.String()
+	}
+	if c.TimeUnit != 0 {
+		out.TimeUnit = c.TimeUnit.String()
+	}
+	return out, nil
+}
+
+// UnmarshalYAML decodes a config, leaving runtime-only fields unset
+func (c *Config) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var in configYAML
+	if err := unmarshal(&in); err != nil {
+		return err
+	}
+
+	cfg := Config{
+		NodeID:          in.NodeID,
+		Layout:          in.Layout,
+		NodeBits:        in.NodeBits,
+		SequenceBits:    in.SequenceBits,
+		Fields:          in.Fields,
+		Fair:            in.Fair,
+		AllowPreEpoch:   in.AllowPreEpoch,
+		Source:          in.Source,
+		SmearBatches:    in.SmearBatches,
+		DatacenterID:    in.DatacenterID,
+		WorkerID:        in.WorkerID,
+		PositiveInt64:   in.PositiveInt64,
+		LifetimeWarning: in.LifetimeWarning,
+	}
+
+	// An embedded layout or a bit split defines the version, so it need
+	// not be registered yet
+	var err error
+	if in.Layout != nil || in.NodeBits != 0 || in.SequenceBits != 0 || in.TimeUnit != "" {
+		cfg.Version, err = parseVersionNumber(in.Version)
+	} else {
+		cfg.Version, err = ParseVersion(in.Version)
+	}
+	if err != nil {
+		return fmt.Errorf("version: %w", err)
+	}
+	if in.NotBefore != "" {
+		if cfg.NotBefore, err = time.Parse(time.RFC3339Nano, in.NotBefore); err != nil {
+			return fmt.Errorf("not_before: %w", err)
+		}
+	}
+	if in.WaitStrategy != "" {
+		if cfg.WaitStrategy, err = parseWaitStrategy(in.WaitStrategy); err != nil {
+			return err
+		}
+	}
+	if in.MinRemaining != "" {
+		if cfg.MinRemaining, err = time.ParseDuration(in.MinRemaining); err != nil {
+			return fmt.Errorf("min_remaining: %w", err)
+		}
+	}
+	if in.TimeUnit != "" {
+		if cfg.TimeUnit, err = time.ParseDuration(in.TimeUnit); err != nil {
+			return fmt.Errorf("time_unit: %w", err)
+		}
+	}
+
+	*c = cfg
+	return nil
+}
+
+// MarshalYAML encodes the layout in text form
+func (l VersionLayout) MarshalYAML() (interface{}, error) {
+	out := layoutYAML{
+		Version:      l.Version.String(),
+		Name:         l.Name,
+		VersionBits:  l.VersionBits,
+		TimeBits:     l.TimeBits,
+		NodeBits:     l.NodeBits,
+		SequenceBits: l.SequenceBits,
+		TimeUnit:     l.TimeUnit.String(),
+		Epoch:        l.Epoch.UTC().Format(time.RFC3339Nano),
+		NodeLast:     l.NodeLast,
+	}
+	for _, f := range l.Fields {
+		out.Fields = append(out.Fields, fieldYAML{Name: f.Name, Bits: f.Bits})
+	}
+	return out, nil
+}
+
+// UnmarshalYAML decodes and validates a layout. The epoch may be given by
+// name (see ParseEpoch).
+func (l *VersionLayout) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var in layoutYAML
+	if err := unmarshal(&in); err != nil {
+		return err
+	}
+
+	// The layout defines its version, so it need not be registered yet
+	version, err := parseVersionNumber(in.Version)
+	if err != nil {
+		return fmt.Errorf("version: %w", err)
+	}
+	unit, err := time.ParseDuration(in.TimeUnit)
+	if err != nil {
+		return fmt.Errorf("time_unit: %w", err)
+	}
+	epoch, err := ParseEpoch(in.Epoch)
+	if err != nil {
+		return fmt.Errorf("epoch: %w", err)
+	}
+
+	layout := VersionLayout{
+		Version:      version,
+		Name:         in.Name,
+		VersionBits:  in.VersionBits,
+		TimeBits:     in.TimeBits,
+		NodeBits:     in.NodeBits,
+		SequenceBits: in.SequenceBits,
+		TimeUnit:     unit,
+		Epoch:        epoch,
+		MaxNodeID:    mask(in.NodeBits),
+		MaxSequence:  mask(in.SequenceBits),
+		MaxTimestamp: mask(in.TimeBits),
+		NodeLast:     in.NodeLast,
+	}
+	for _, f := range in.Fields {
+		layout.Fields = append(layout.Fields, Field{Name: f.Name, Bits: f.Bits})
+	}
+
+	if err := layout.validate(); err != nil {
+		return err
+	}
+	*l = layout
+	return nil
+}
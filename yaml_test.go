@@ -0,0 +1,160 @@
+package snowflake
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// yamlRoundTrip feeds the value produced by MarshalYAML back through an
+// UnmarshalYAML callback, as a YAML library would after parsing the document.
+func yamlRoundTrip(marshaled interface{}) func(interface{}) error {
+	return func(out interface{}) error {
+		reflect.ValueOf(out).Elem().Set(reflect.ValueOf(marshaled))
+		return nil
+	}
+}
+
+func TestConfig_YAMLRoundTrip(t *testing.T) {
+	layout, _ := LookupLayout(Version0)
+	cfg := Config{
+		Version:      Version0,
+		NodeID:       7,
+		Layout:       &layout,
+		NotBefore:    time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC),
+		WaitStrategy: WaitSleep,
+		Fair:         true,
+		MinRemaining: 24 * time.Hour,
+	}
+
+	out, err := cfg.MarshalYAML()
+	if err != nil {
+		t.Fatalf("Failed to marshal config: %v", err)
+	}
+	spec := out.(configYAML)
+	if spec.WaitStrategy != "sleep" || spec.MinRemaining != "24h0m0s" {
+		t.Errorf("Unexpected text forms: %+v", spec)
+	}
+
+	var got Config
+	if err := got.UnmarshalYAML(yamlRoundTrip(out)); err != nil {
+		t.Fatalf("Failed to unmarshal config: %v", err)
+	}
+	if got.NodeID != 7 || got.WaitStrategy != WaitSleep || !got.Fair ||
+		got.MinRemaining != cfg.MinRemaining || !got.NotBefore.Equal(cfg.NotBefore) {
+		t.Errorf("Round trip mismatch: %+v", got)
+	}
+
+	spec.WaitStrategy = "spin"
+	if err := got.UnmarshalYAML(yamlRoundTrip(spec)); err == nil {
+		t.Error("Expected error for unknown wait strategy")
+	}
+}
+
+func TestConfig_YAMLBitSplit(t *testing.T) {
+	out, _ := Config{Version: 6, NodeBits: 10, SequenceBits: 10, TimeUnit: time.Second}.MarshalYAML()
+	spec := out.(configYAML)
+	if spec.Version != "v6" || spec.NodeBits != 10 || spec.SequenceBits != 10 || spec.TimeUnit != "1s" {
+		t.Errorf("Unexpected text forms: %+v", spec)
+	}
+
+	// Version 6 is not registered until a generator uses the split
+	var got Config
+	if err := got.UnmarshalYAML(yamlRoundTrip(spec)); err != nil {
+		t.Fatalf("Failed to unmarshal config: %v", err)
+	}
+	if got.Version != 6 || got.NodeBits != 10 || got.SequenceBits != 10 || got.TimeUnit != time.Second {
+		t.Errorf("Round trip mismatch: %+v", got)
+	}
+
+	spec.NodeBits, spec.SequenceBits, spec.TimeUnit = 0, 0, ""
+	if err := got.UnmarshalYAML(yamlRoundTrip(spec)); err == nil {
+		t.Error("Expected error for an unregistered version without a split")
+	}
+}
+
+func TestVersionLayout_YAMLRoundTrip(t *testing.T) {
+	layout, _ := LookupLayout(Version0)
+
+	out, err := layout.MarshalYAML()
+	if err != nil {
+		t.Fatalf("Failed to marshal layout: %v", err)
+	}
+
+	var got VersionLayout
+	if err := got.UnmarshalYAML(yamlRoundTrip(out)); err != nil {
+		t.Fatalf("Failed to unmarshal layout: %v", err)
+	}
+	if !reflect.DeepEqual(got, layout) {
+		t.Errorf("Expected %+v, got %+v", layout, got)
+	}
+
+	spec := out.(layoutYAML)
+	spec.Epoch = "y2026"
+	if err := got.UnmarshalYAML(yamlRoundTrip(spec)); err != nil || !got.Epoch.Equal(EpochY2026) {
+		t.Errorf("Expected named epoch to resolve, got %v (%v)", got.Epoch, err)
+	}
+
+	spec.NodeBits++
+	if err := got.UnmarshalYAML(yamlRoundTrip(spec)); err == nil {
+		t.Error("Expected validation error for oversized layout")
+	}
+}
+
+func TestVersion_YAML(t *testing.T) {
+	out, _ := Version0.MarshalYAML()
+	if out != "v0" {
+		t.Errorf("Expected v0, got %v", out)
+	}
+
+	var v Version = 1
+	if err := v.UnmarshalYAML(yamlRoundTrip("v0")); err != nil || v != Version0 {
+		t.Errorf("Expected Version0, got %v (%v)", v, err)
+	}
+}
+
+// TestConfig_YAMLDocument decodes a service config file with a real YAML
+// library, so the nested layout goes through its own UnmarshalYAML
+func TestConfig_YAMLDocument(t *testing.T) {
+	doc := `
+version: v6
+node_id: 12
+layout:
+  version: v6
+  name: custom
+  version_bits: 3
+  time_bits: 41
+  node_bits: 10
+  sequence_bits: 10
+  time_unit: 1ms
+  epoch: y2026
+`
+	var cfg Config
+	if err := yaml.Unmarshal([]byte(doc), &cfg); err != nil {
+		t.Fatalf("Failed to decode config: %v", err)
+	}
+	if cfg.Version != 6 || cfg.NodeID != 12 || cfg.Layout == nil {
+		t.Fatalf("Unexpected config: %+v", cfg)
+	}
+	if cfg.Layout.Version != 6 || cfg.Layout.MaxNodeID != 1023 || !cfg.Layout.Epoch.Equal(EpochY2026) {
+		t.Errorf("Unexpected layout: %+v", cfg.Layout)
+	}
+
+	out, err := yaml.Marshal(cfg)
+	if err != nil {
+		t.Fatalf("Failed to encode config: %v", err)
+	}
+	var again Config
+	if err := yaml.Unmarshal(out, &again); err != nil {
+		t.Fatalf("Failed to decode encoded config: %v\n%s", err, out)
+	}
+	if again.Layout == nil || again.Layout.Version != 6 || again.Layout.TimeBits != 41 {
+		t.Errorf("Round trip mismatch:\n%s", out)
+	}
+
+	if _, ok := LookupLayout(6); ok {
+		t.Error("Decoding a config should not register its layout")
+	}
+}